@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes for `csys check`.
+const (
+	checkExitFailed = 1
+	checkExitError  = 2
+)
+
+var checkRules = system.CheckRules{Thresholds: system.DefaultThresholds}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: display.CheckShort,
+	Long:  display.CheckLong,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, port := range append(checkRules.PortsFree, checkRules.PortsListening...) {
+			if port <= 0 || port > 65535 {
+				return fmt.Errorf("port %d out of range (must be 1-65535)", port)
+			}
+		}
+		checkRules.PortProtocol = strings.ToLower(checkRules.PortProtocol)
+		if checkRules.PortProtocol != "tcp" && checkRules.PortProtocol != "udp" {
+			return fmt.Errorf("invalid protocol %q (must be tcp or udp)", checkRules.PortProtocol)
+		}
+		if checkRules.Thresholds.Warning > checkRules.Thresholds.Critical {
+			return fmt.Errorf("--warn (%.0f) must not be above --crit (%.0f)",
+				checkRules.Thresholds.Warning, checkRules.Thresholds.Critical)
+		}
+
+		runCheck()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	usageExitCodes[checkCmd] = checkExitError
+
+	f := checkCmd.Flags()
+	f.Float64Var(&checkRules.DiskMax, "disk-max", 0, "Fail when disk usage is above this percent (default --crit)")
+	f.Float64Var(&checkRules.MemMax, "mem-max", 0, "Fail when memory usage is above this percent (default --crit)")
+	f.Float64Var(&checkRules.CPUMax, "cpu-max", 0, "Fail when CPU usage is above this percent (default --crit)")
+	f.BoolVar(&checkRules.AllDisks, "all-disks", false, "Check every partition instead of only the root volume")
+	f.IntSliceVar(&checkRules.PortsFree, "port-free", nil, "Fail when something is listening on this port (repeatable)")
+	f.IntSliceVar(&checkRules.PortsListening, "port-listening", nil, "Fail when nothing is listening on this port (repeatable)")
+	f.StringVarP(&checkRules.PortProtocol, "protocol", "p", "tcp", "Protocol for --port-free and --port-listening: tcp or udp")
+	f.Float64Var(&checkRules.Thresholds.Warning, "warn", system.DefaultThresholds.Warning, "Usage percent reported as a warning")
+	f.Float64Var(&checkRules.Thresholds.Critical, "crit", system.DefaultThresholds.Critical, "Default failure percent for disk, memory and CPU")
+	f.DurationVar(&checkRules.CPUWindow, "sample", time.Second, "How long to measure CPU usage for")
+}
+
+func runCheck() {
+	display.SetThresholds(checkRules.Thresholds)
+
+	report, err := system.RunChecks(checkRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running checks: %v\n", err)
+		os.Exit(checkExitError)
+	}
+
+	err = writeResult("check", report, func() string {
+		return display.FormatCheckReport(report)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", outputFormat, err)
+		os.Exit(checkExitError)
+	}
+
+	if !report.Passed {
+		os.Exit(checkExitFailed)
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var memCmd = &cobra.Command{
+	Use:   "mem",
+	Short: display.MemShort,
+	Long:  display.MemLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		memInfo, err := system.GetMemoryInfo()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting memory info: %v\n", err)
+			return
+		}
+
+		printResult("memory", memInfo, func() string {
+			return display.FormatMemoryDetail(memInfo)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(memCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var netSample time.Duration
+
+var netCmd = &cobra.Command{
+	Use:   "net",
+	Short: display.NetShort,
+	Long:  display.NetLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		netInfo, err := system.GetNetworkInfo(netSample)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting network info: %v\n", err)
+			return
+		}
+
+		printResult("network", netInfo, func() string {
+			return display.FormatNetworkList(netInfo)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(netCmd)
+
+	netCmd.Flags().DurationVar(&netSample, "sample", time.Second, "How long to measure throughput for")
+}
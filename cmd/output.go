@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iyushkarki/csys/internal/output"
+)
+
+var (
+	outputFlag   string
+	outputFormat = output.Table
+)
+
+// printResult writes data as a versioned JSON/YAML document when a structured
+// --output format was requested, and falls back to the rendered table view.
+// A document that cannot be written exits 1 so scripts do not mistake empty
+// output for success.
+func printResult(kind string, data any, render func() string) {
+	if err := writeResult(kind, data, render); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", outputFormat, err)
+		os.Exit(1)
+	}
+}
+
+// writeResult is printResult for commands with their own exit codes: it
+// returns the encoding error instead of exiting.
+func writeResult(kind string, data any, render func() string) error {
+	if !outputFormat.IsStructured() {
+		fmt.Println(render())
+		return nil
+	}
+	return output.Write(os.Stdout, outputFormat, kind, data)
+}
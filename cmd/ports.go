@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/iyushkarki/csys/internal/display"
 	"github.com/iyushkarki/csys/internal/system"
@@ -16,8 +20,8 @@ var portsCmd = &cobra.Command{
 	Use:   "ports",
 	Short: display.PortsShort,
 	Long:  display.PortsLong,
-	Run: func(cmd *cobra.Command, args []string) {
-		runPortsList()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPortsList()
 	},
 }
 
@@ -25,19 +29,178 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: display.ListShort,
 	Long:  display.ListLong,
-	Run: func(cmd *cobra.Command, args []string) {
-		runPortsList()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPortsList()
 	},
 }
 
+var (
+	killName      string
+	killAllDev    bool
+	killProtocol  string
+	killYes       bool
+	killForce     bool
+	killSignal    string
+	killGrace     time.Duration
+	killTree      bool
+	killGroup     bool
+	killContainer bool
+)
+
 var killCmd = &cobra.Command{
-	Use:   "kill <port> [port2] [port3]...",
+	Use:   "kill [port|range]...",
 	Short: display.KillShort,
 	Long:  display.KillLong,
-	Args:  cobra.MinimumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && killName == "" && !killAllDev {
+			return fmt.Errorf("give at least one port or range, --name or --all-dev")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sig, err := system.ParseSignal(killSignal)
+		if err != nil {
+			return err
+		}
+		// --force predates --yes and --signal and meant both.
+		if killForce {
+			killYes = true
+			sig = syscall.SIGKILL
+		}
+		if killGrace <= 0 {
+			return fmt.Errorf("--grace must be positive")
+		}
+
+		scope := system.ScopeProcess
+		switch {
+		case boolCount(killTree, killGroup, killContainer) > 1:
+			return fmt.Errorf("only one of --tree, --group and --container can be given")
+		case killTree:
+			scope = system.ScopeTree
+		case killGroup:
+			scope = system.ScopeGroup
+		case killContainer:
+			scope = system.ScopeContainer
+		}
+
+		selector := system.KillSelector{
+			Name:     killName,
+			AllDev:   killAllDev,
+			Protocol: strings.ToLower(killProtocol),
+		}
+		if selector.Protocol != "" && selector.Protocol != "tcp" && selector.Protocol != "udp" {
+			return fmt.Errorf("invalid protocol %q (must be tcp or udp)", killProtocol)
+		}
+		for _, arg := range args {
+			r, err := system.ParsePortRange(arg)
+			if err != nil {
+				return err
+			}
+			selector.Ranges = append(selector.Ranges, r)
+		}
+
+		runPortsKill(selector, system.KillOptions{Signal: sig, Grace: killGrace}, scope)
+		return nil
+	},
+}
+
+var (
+	listRange    string
+	freeRange    string
+	freeCount    int
+	freeProtocol string
+)
+
+var freeCmd = &cobra.Command{
+	Use:   "free",
+	Short: display.FreeShort,
+	Long:  display.FreeLong,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r := system.DefaultFreePortRange
+		if freeRange != "" {
+			var err error
+			if r, err = system.ParsePortRange(freeRange); err != nil {
+				return err
+			}
+		}
+		if freeCount <= 0 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		runPortsFree(r, freeCount, strings.ToLower(freeProtocol))
+		return nil
+	},
+}
+
+var (
+	connsStates []string
+	connsPort   int
+)
+
+var connsCmd = &cobra.Command{
+	Use:   "conns",
+	Short: display.ConnsShort,
+	Long:  display.ConnsLong,
+	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		force, _ := cmd.Flags().GetBool("force")
-		runPortsKill(args, force)
+		runPortsConns()
+	},
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: display.AuditShort,
+	Long:  display.AuditLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runPortsAudit()
+	},
+}
+
+// Exit codes for `csys ports wait`.
+const (
+	waitExitTimeout = 1
+	waitExitError   = 2
+)
+
+var (
+	waitTimeout  time.Duration
+	waitState    string
+	waitHost     string
+	waitProtocol string
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait <port> [port2] [port3]...",
+	Short: display.WaitShort,
+	Long:  display.WaitLong,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ports, err := parsePorts(args)
+		if err != nil {
+			return err
+		}
+		state, err := system.ParsePortWaitState(waitState)
+		if err != nil {
+			return err
+		}
+		protocol := strings.ToLower(waitProtocol)
+		if protocol != "tcp" && protocol != "udp" {
+			return fmt.Errorf("invalid protocol %q (must be tcp or udp)", waitProtocol)
+		}
+		if protocol == "udp" && waitHost != "" {
+			return fmt.Errorf("--host probes with a TCP connect and cannot be used with --protocol udp")
+		}
+
+		runPortsWait(system.PortWaitOptions{
+			Ports:    ports,
+			State:    state,
+			Timeout:  waitTimeout,
+			Host:     waitHost,
+			Protocol: protocol,
+		})
+		return nil
 	},
 }
 
@@ -45,59 +208,269 @@ func init() {
 	rootCmd.AddCommand(portsCmd)
 	portsCmd.AddCommand(listCmd)
 	portsCmd.AddCommand(killCmd)
-	killCmd.Flags().BoolP("force", "f", false, "Force kill with SIGKILL")
+	portsCmd.AddCommand(connsCmd)
+	portsCmd.AddCommand(auditCmd)
+	portsCmd.AddCommand(waitCmd)
+	usageExitCodes[waitCmd] = waitExitError
+	portsCmd.AddCommand(freeCmd)
+	for _, c := range []*cobra.Command{portsCmd, listCmd} {
+		c.Flags().StringVarP(&listRange, "range", "r", "", "Only list ports in this range, e.g. 8000-9000")
+	}
+	freeCmd.Flags().StringVarP(&freeRange, "range", "r", "", "Range to search, e.g. 3000-3999 (default 1024-49151)")
+	freeCmd.Flags().IntVarP(&freeCount, "count", "n", 1, "How many free ports to return")
+	freeCmd.Flags().StringVarP(&freeProtocol, "protocol", "p", "tcp", "Protocol the ports must be free for: tcp or udp")
+	connsCmd.Flags().StringSliceVarP(&connsStates, "state", "s", nil, "Only show these states, e.g. established,close_wait (all = include LISTEN)")
+	connsCmd.Flags().IntVarP(&connsPort, "port", "p", 0, "Only show connections with this local or remote port")
+	waitCmd.Flags().DurationVarP(&waitTimeout, "timeout", "t", 30*time.Second, "Give up after this long (0 waits forever)")
+	waitCmd.Flags().StringVarP(&waitState, "state", "s", string(system.PortListening), "State to wait for: listening or free")
+	waitCmd.Flags().StringVarP(&waitProtocol, "protocol", "p", "tcp", "Protocol to wait for: tcp or udp")
+	waitCmd.Flags().StringVar(&waitHost, "host", "", "Probe with a TCP connect to this host instead of reading the local socket table")
+	killCmd.Flags().StringVar(&killName, "name", "", "Kill processes with this name (exact, case-insensitive)")
+	killCmd.Flags().BoolVar(&killAllDev, "all-dev", false, "Kill everything you own listening on ports 1024-65535")
+	killCmd.Flags().StringVarP(&killProtocol, "protocol", "p", "", "Only match tcp or udp sockets")
+	killCmd.Flags().BoolVarP(&killYes, "yes", "y", false, "Skip the confirmation prompt")
+	killCmd.Flags().StringVarP(&killSignal, "signal", "s", "TERM", "Signal to send first: TERM, INT, HUP, QUIT, KILL, USR1, USR2 or a number")
+	killCmd.Flags().DurationVarP(&killGrace, "grace", "g", 5*time.Second, "How long to wait for the process to exit before sending SIGKILL")
+	killCmd.Flags().BoolVar(&killTree, "tree", false, "Also kill every child process of the port owner")
+	killCmd.Flags().BoolVar(&killGroup, "group", false, "Kill the port owner's whole process group, including its parent shell or supervisor")
+	killCmd.Flags().BoolVar(&killContainer, "container", false, "Stop the container the port belongs to instead of the process (e.g. docker-proxy)")
+	killCmd.Flags().BoolVarP(&killForce, "force", "f", false, "Same as --yes --signal KILL")
+	killCmd.Flags().MarkDeprecated("force", "use --yes --signal KILL instead")
 }
 
-func runPortsList() {
+func runPortsList() error {
+	var r *system.PortRange
+	if listRange != "" {
+		parsed, err := system.ParsePortRange(listRange)
+		if err != nil {
+			return err
+		}
+		r = &parsed
+	}
+
 	ports, err := system.GetListeningPorts()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting listening ports: %v\n", err)
+		return nil
+	}
+	if r != nil {
+		ports = system.FilterPorts(ports, *r)
+	}
+
+	printResult("ports", ports, func() string {
+		return display.FormatPortsList(ports)
+	})
+	return nil
+}
+
+func runPortsFree(r system.PortRange, count int, protocol string) {
+	free, err := system.FindFreePorts(r, count, protocol)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding free ports: %v\n", err)
 		return
 	}
 
-	output := display.FormatPortsList(ports)
-	fmt.Println(output)
+	printResult("free_ports", free, func() string {
+		return display.FormatFreePorts(free, count)
+	})
 }
 
-func runPortsKill(portStrings []string, force bool) {
-	var ports []int
-	for _, portStr := range portStrings {
-		port, err := strconv.Atoi(portStr)
+func runPortsConns() {
+	report, err := system.GetConnections(system.ConnectionFilter{
+		States: connsStates,
+		Port:   connsPort,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting connections: %v\n", err)
+		return
+	}
+
+	printResult("connections", report, func() string {
+		return display.FormatConnectionsList(report)
+	})
+}
+
+func runPortsAudit() {
+	ports, err := system.GetListeningPorts()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting listening ports: %v\n", err)
+		return
+	}
+
+	report := system.AuditPorts(ports)
+	printResult("audit", report, func() string {
+		return display.FormatAuditReport(report)
+	})
+}
+
+func runPortsWait(opts system.PortWaitOptions) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := system.WaitForPorts(ctx, opts)
+	if report == nil {
+		fmt.Fprintf(os.Stderr, "Error waiting for ports: %v\n", err)
+		os.Exit(waitExitError)
+	}
+
+	err = writeResult("wait", report, func() string {
+		return display.FormatPortWaitReport(report)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", outputFormat, err)
+		os.Exit(waitExitError)
+	}
+
+	if !report.Ready {
+		os.Exit(waitExitTimeout)
+	}
+}
+
+func parsePorts(args []string) ([]int, error) {
+	ports := make([]int, 0, len(args))
+	for _, arg := range args {
+		port, err := strconv.Atoi(arg)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Invalid port '%s' (must be a number)\n", portStr)
-			fmt.Fprintf(os.Stderr, "Use 'csys ports kill --help' for usage examples\n")
-			return
+			return nil, fmt.Errorf("invalid port '%s' (must be a number)", arg)
 		}
 		if port <= 0 || port > 65535 {
-			fmt.Fprintf(os.Stderr, "Error: Port %d out of range (must be 1-65535)\n", port)
-			fmt.Fprintf(os.Stderr, "Use 'csys ports kill --help' for usage examples\n")
-			return
+			return nil, fmt.Errorf("port %d out of range (must be 1-65535)", port)
 		}
 		ports = append(ports, port)
 	}
+	return ports, nil
+}
 
-	for _, port := range ports {
-		portInfo, err := system.GetProcessOnPort(port)
-		if err != nil {
-			fmt.Println(display.FormatPortNotFound(port))
-			continue
-		}
+func runPortsKill(selector system.KillSelector, opts system.KillOptions, scope system.KillScope) {
+	ports, err := system.GetListeningPorts()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting listening ports: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Prompts go to stderr when stdout carries a JSON/YAML document.
+	prompt := os.Stdout
+	if outputFormat.IsStructured() {
+		prompt = os.Stderr
+	}
+
+	results := make([]system.KillResult, 0)
+	targets, err := system.ExpandKillTargets(system.SelectKillTargets(ports, selector), scope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing related processes: %v\n", err)
+		os.Exit(1)
+	}
 
-		if !force {
-			fmt.Println(display.FormatKillConfirmation(portInfo))
-			reader := bufio.NewReader(os.Stdin)
-			text, _ := reader.ReadString('\n')
-			if strings.ToLower(strings.TrimSpace(text)) != "y" {
-				fmt.Println("Kill cancelled")
+	// --container never falls back to killing a host process.
+	if scope == system.ScopeContainer {
+		inContainers := targets[:0]
+		for _, t := range targets {
+			if t.StopContainer {
+				inContainers = append(inContainers, t)
 				continue
 			}
+			ports := t.PortNumbers()
+			results = append(results, system.KillResult{
+				Port:        ports[0],
+				Ports:       ports,
+				PID:         t.PID,
+				ProcessName: t.ProcessName,
+				Error:       "not in a container",
+			})
+			fmt.Fprintln(prompt, display.FormatNotInContainer(t))
 		}
+		targets = inContainers
+	}
 
-		err = system.KillProcessOnPort(port, force)
-		if err != nil {
-			fmt.Println(display.FormatKillError(port, err))
+	// A port named on its own that is not listening is reported, as before
+	// ranges were accepted.
+	for _, r := range selector.Ranges {
+		if r.Start != r.End || len(system.FilterPorts(ports, r)) > 0 {
+			continue
+		}
+		results = append(results, system.KillResult{
+			Port:  r.Start,
+			Ports: []int{r.Start},
+			Error: fmt.Sprintf("port %d not found", r.Start),
+		})
+		fmt.Fprintln(prompt, display.FormatPortNotFound(r.Start))
+	}
+
+	if len(targets) == 0 {
+		if len(results) == 0 {
+			fmt.Fprintln(prompt, display.FormatNoKillTargets())
+		}
+		if outputFormat.IsStructured() {
+			printResult("kill", results, nil)
+		}
+		// Nothing matched, or nothing that matched could be killed.
+		os.Exit(1)
+	}
+
+	confirmed := killYes
+	if !confirmed {
+		fmt.Fprintln(prompt, display.FormatKillConfirmation(targets, opts))
+		reader := bufio.NewReader(os.Stdin)
+		text, _ := reader.ReadString('\n')
+		confirmed = strings.ToLower(strings.TrimSpace(text)) == "y"
+		if !confirmed {
+			fmt.Fprintln(prompt, "Kill cancelled")
+		}
+	}
+
+	for _, target := range targets {
+		var result system.KillResult
+		if confirmed {
+			result = system.TerminateTarget(target, opts)
 		} else {
-			fmt.Println(display.FormatKillSuccess(port, portInfo.ProcessName))
+			ports := target.PortNumbers()
+			result = system.KillResult{
+				Port:        ports[0],
+				Ports:       ports,
+				PID:         target.PID,
+				ProcessName: target.ProcessName,
+				Error:       "cancelled",
+			}
+		}
+
+		if confirmed && !outputFormat.IsStructured() {
+			if result.Killed {
+				fmt.Println(display.FormatKillSuccess(target, result, opts))
+			} else {
+				fmt.Println(display.FormatKillError(target, result))
+			}
+		}
+		results = append(results, result)
+	}
+
+	if outputFormat.IsStructured() {
+		printResult("kill", results, nil)
+	}
+	if killFailed(results) {
+		os.Exit(1)
+	}
+}
+
+// killFailed reports whether any target was skipped, missing or survived.
+// Declining the prompt is not a failure.
+func killFailed(results []system.KillResult) bool {
+	for _, r := range results {
+		if r.Error == "cancelled" {
+			continue
+		}
+		if !r.Killed || r.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func boolCount(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
 		}
 	}
+	return n
 }
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var (
+	procSample time.Duration
+	procEnv    bool
+)
+
+var procCmd = &cobra.Command{
+	Use:   "proc <pid|name>",
+	Short: display.ProcShort,
+	Long:  display.ProcLong,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runProc(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(procCmd)
+
+	procCmd.Flags().DurationVar(&procSample, "sample", time.Second, "How long to measure CPU usage for")
+	procCmd.Flags().BoolVar(&procEnv, "env", false, "Also show the environment (may contain secrets)")
+}
+
+func runProc(query string) {
+	pids, err := system.FindProcesses(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	procs, err := system.GetProcessDetails(pids, system.ProcessDetailOptions{
+		Window: procSample,
+		Env:    procEnv,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting process info: %v\n", err)
+		return
+	}
+
+	if len(procs) == 1 {
+		printResult("process", procs[0], func() string {
+			return display.FormatProcessDetail(procs[0])
+		})
+		return
+	}
+
+	printResult("processes", procs, func() string {
+		return display.FormatProcessMatches(query, procs)
+	})
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var (
+	procsSort    string
+	procsLimit   int
+	procsSample  time.Duration
+	procsTree    bool
+	procsGroupBy string
+	procsGroup   string
+)
+
+var procsCmd = &cobra.Command{
+	Use:   "procs",
+	Short: display.ProcsShort,
+	Long:  display.ProcsLong,
+	Args:  cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if procsGroupBy != "" {
+			if _, err := system.ParseProcessGroupKey(procsGroupBy); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		// The tree shows every process unless a limit is asked for.
+		if procsTree && !cmd.Flags().Changed("limit") {
+			procsLimit = 0
+		}
+		runProcs()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(procsCmd)
+
+	procsCmd.Flags().StringVarP(&procsSort, "sort", "s", "cpu", "Sort by: cpu, rss, vms, threads, files, start, pid")
+	procsCmd.Flags().IntVarP(&procsLimit, "limit", "l", 15, "Number of processes to show (0 = all)")
+	procsCmd.Flags().BoolVarP(&procsTree, "tree", "t", false, "Show the parent/child hierarchy with per-subtree CPU and memory")
+	procsCmd.Flags().StringVarP(&procsGroupBy, "group-by", "g", "", "Sum CPU, memory and process count per group: name, exe, user, cgroup")
+	procsCmd.Flags().StringVar(&procsGroup, "group", "", "List the processes of one group (by --group-by, default name)")
+	procsCmd.Flags().DurationVar(&procsSample, "sample", time.Second, "How long to measure per-process CPU usage for")
+	procsCmd.MarkFlagsMutuallyExclusive("tree", "group-by")
+	procsCmd.MarkFlagsMutuallyExclusive("tree", "group")
+}
+
+func runProcs() {
+	sortBy, err := system.ParseProcessSortKey(procsSort)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	query := system.ProcessQuery{
+		SortBy: sortBy,
+		Limit:  procsLimit,
+		Window: procsSample,
+	}
+	// Groups and drill-downs are limited after aggregating or filtering.
+	if procsTree || procsGroupBy != "" || procsGroup != "" {
+		query.Limit = 0
+	}
+
+	procs, err := system.QueryProcesses(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting process info: %v\n", err)
+		return
+	}
+	system.AttachContainers(procs)
+
+	if procsTree {
+		roots := system.BuildProcessTree(procs, sortBy)
+		printResult("process_tree", roots, func() string {
+			return display.FormatProcessTree(roots, sortBy, procsLimit)
+		})
+		return
+	}
+
+	if procsGroupBy != "" || procsGroup != "" {
+		runProcsGroups(procs, sortBy)
+		return
+	}
+
+	printResult("processes", procs, func() string {
+		return display.FormatProcessList(procs, sortBy)
+	})
+}
+
+// runProcsGroups aggregates procs by --group-by, or with --group lists the
+// members of one group.
+func runProcsGroups(procs []system.ProcessInfo, sortBy system.ProcessSortKey) {
+	key := system.GroupByName
+	if procsGroupBy != "" {
+		key, _ = system.ParseProcessGroupKey(procsGroupBy)
+	}
+
+	if procsGroup != "" {
+		members := limitProcesses(system.FilterProcessGroup(procs, key, procsGroup), procsLimit)
+		if len(members) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no processes in %s group %q\n", key, procsGroup)
+			return
+		}
+		printResult("processes", members, func() string {
+			return display.FormatProcessList(members, sortBy)
+		})
+		return
+	}
+
+	groups := system.GroupProcesses(procs, key)
+	system.SortProcessGroups(groups, sortBy)
+	if procsLimit > 0 && len(groups) > procsLimit {
+		groups = groups[:procsLimit]
+	}
+
+	printResult("process_groups", groups, func() string {
+		return display.FormatProcessGroups(groups, key, sortBy)
+	})
+}
+
+func limitProcesses(procs []system.ProcessInfo, limit int) []system.ProcessInfo {
+	if limit > 0 && len(procs) > limit {
+		return procs[:limit]
+	}
+	return procs
+}
@@ -3,15 +3,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/output"
 	"github.com/iyushkarki/csys/internal/system"
+	"github.com/iyushkarki/csys/internal/tui"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 var Version = "dev"
-var liveMode bool
+
+var (
+	liveMode     bool
+	liveInterval time.Duration
+	liveCount    int
+	cpuDetail    bool
+	sampleWindow time.Duration
+	groupByFlag  string
+	groupBy      system.ProcessGroupKey
+)
 
 var rootCmd = &cobra.Command{
 	Use:               "csys",
@@ -19,7 +33,39 @@ var rootCmd = &cobra.Command{
 	Long:              display.RootLong,
 	Version:           Version,
 	CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(outputFlag)
+		if err != nil {
+			return err
+		}
+		outputFormat = format
+		return nil
+	},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if liveInterval <= 0 {
+			return fmt.Errorf("--interval must be positive, got %s", liveInterval)
+		}
+		if sampleWindow < 0 {
+			return fmt.Errorf("--sample must not be negative, got %s", sampleWindow)
+		}
+		if liveCount < 0 {
+			return fmt.Errorf("--count must not be negative, got %d", liveCount)
+		}
+		if groupByFlag != "" {
+			key, err := system.ParseProcessGroupKey(groupByFlag)
+			if err != nil {
+				return err
+			}
+			groupBy = key
+		}
+		return cobra.NoArgs(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		// --interval and --count only make sense for live mode, so either one implies it.
+		if cmd.Flags().Changed("interval") || cmd.Flags().Changed("count") {
+			liveMode = true
+		}
+
 		if liveMode {
 			runLiveMode()
 		} else {
@@ -28,63 +74,124 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// usageExitCodes are the exit codes for invalid flags or arguments of the
+// commands that document their own, so scripts can tell a typo from a
+// failed check; every other command exits 1.
+var usageExitCodes = map[*cobra.Command]int{}
+
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
+		if code, ok := usageExitCodes[cmd]; ok {
+			os.Exit(code)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
-	rootCmd.Flags().BoolVarP(&liveMode, "live", "l", false, "Enable live monitoring mode (updates every 2 seconds)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "Output format: table, json or yaml")
+	rootCmd.Flags().BoolVarP(&liveMode, "live", "l", false, "Interactive live dashboard (updates every --interval)")
+	rootCmd.Flags().DurationVarP(&liveInterval, "interval", "i", 2*time.Second, "Refresh interval for live mode (e.g. 500ms, 5s)")
+	rootCmd.Flags().BoolVar(&cpuDetail, "cpu-detail", false, "Show per-core CPU usage, load per core and CPU time split")
+	rootCmd.Flags().DurationVar(&sampleWindow, "sample", system.DefaultSampleWindow, "How long to measure CPU usage for before the first reading")
+	rootCmd.Flags().IntVarP(&liveCount, "count", "n", 0, "Stop live mode after this many samples (0 = run until quit)")
+	rootCmd.Flags().StringVar(&groupByFlag, "group-by", "", "Show the top process groups instead of processes: name, exe, user, cgroup")
 }
 
-func runSnapshot() {
-	runSnapshotWithTime(time.Time{})
+func newCollector() *system.Collector {
+	collector := system.NewCollector(5, sampleWindow)
+	collector.SetGroupBy(groupBy)
+	return collector
 }
 
-func runSnapshotWithTime(timestamp time.Time) {
-	diskInfo, err := system.GetDiskInfo()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting disk info: %v\n", err)
-		return
-	}
-
-	memInfo, err := system.GetMemoryInfo()
+func runSnapshot() {
+	overview, err := newCollector().Collect()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting memory info: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error getting system overview: %v\n", err)
 		return
 	}
 
-	cpuPercent, err := system.GetCPUUsage()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting CPU info: %v\n", err)
-		return
-	}
+	printResult("overview", overview, func() string {
+		return display.FormatSystemOverviewWithOptions(overview, display.OverviewOptions{
+			CPUDetail: cpuDetail,
+		})
+	})
+}
 
-	topProcs, err := system.GetTopProcessesByMemory(5)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting process info: %v\n", err)
-		return
-	}
+func runLiveMode() {
+	collector := newCollector()
+	recorder := system.NewSessionRecorder()
 
-	if timestamp.IsZero() {
-		output := display.FormatSystemOverview(diskInfo, memInfo, cpuPercent, topProcs)
-		fmt.Println(output)
+	// The dashboard needs a real terminal; scripts and --output json/yaml
+	// get one snapshot per interval on stdout instead.
+	if outputFormat.IsStructured() || !isatty.IsTerminal(os.Stdout.Fd()) {
+		runPlainLiveMode(collector, recorder)
 	} else {
-		output := display.FormatSystemOverviewWithTime(diskInfo, memInfo, cpuPercent, topProcs, timestamp)
-		fmt.Println(output)
+		err := tui.Run(tui.Options{
+			Interval:  liveInterval,
+			Count:     liveCount,
+			CPUDetail: cpuDetail,
+			GroupBy:   groupBy,
+			Collector: collector,
+			Recorder:  recorder,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running live mode: %v\n", err)
+		}
 	}
+
+	summary := recorder.Summary()
+	printResult("session_summary", summary, func() string {
+		return display.FormatSessionSummary(summary)
+	})
 }
 
-func runLiveMode() {
-	ticker := time.NewTicker(2 * time.Second)
+func runPlainLiveMode(collector *system.Collector, recorder *system.SessionRecorder) {
+	ticker := time.NewTicker(liveInterval)
 	defer ticker.Stop()
 
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	// Pipes and files get every sample one after another, not escape codes.
+	tty := isatty.IsTerminal(os.Stdout.Fd())
+
 	for {
-		clearScreen()
-		runSnapshotWithTime(time.Now())
-		<-ticker.C
+		now := time.Now()
+		overview, err := collector.Collect()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting system overview: %v\n", err)
+		} else {
+			recorder.Record(overview, now)
+			if !outputFormat.IsStructured() {
+				if tty {
+					clearScreen()
+				} else if recorder.Samples() > 1 {
+					fmt.Println()
+				}
+			}
+			printResult("overview", overview, func() string {
+				rxHistory, txHistory := recorder.NetHistory()
+				return display.FormatSystemOverviewWithOptions(overview, display.OverviewOptions{
+					Timestamp: now,
+					CPUDetail: cpuDetail,
+					RxHistory: rxHistory,
+					TxHistory: txHistory,
+				})
+			})
+		}
+
+		if liveCount > 0 && recorder.Samples() >= liveCount {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-sigs:
+			return
+		}
 	}
 }
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/iyushkarki/csys/internal/display"
 	"github.com/iyushkarki/csys/internal/system"
@@ -10,8 +11,9 @@ import (
 )
 
 var (
-	scanPath  string
-	scanLimit int
+	scanPath       string
+	scanLimit      int
+	scanDiskSample time.Duration
 )
 
 var scanCmd = &cobra.Command{
@@ -34,7 +36,9 @@ var scanCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Println(display.RenderScanResult(result))
+		printResult("scan", result, func() string {
+			return display.RenderScanResult(result)
+		})
 	},
 }
 
@@ -45,12 +49,13 @@ func init() {
 	scanCmd.Flags().IntVarP(&scanLimit, "limit", "l", 10, "Number of top items to show")
 
 	scanCmd.AddCommand(scanDiskCmd)
+	scanDiskCmd.Flags().DurationVar(&scanDiskSample, "sample", time.Second, "How long to measure disk I/O for (0 to skip)")
 }
 
 var scanDiskCmd = &cobra.Command{
 	Use:   "disk",
-	Short: "Show usage of all disk partitions",
-	Long:  `Scan and display storage usage for all mounted disk partitions.`,
+	Short: display.ScanDiskShort,
+	Long:  display.ScanDiskLong,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		info, err := system.GetFullDiskInfo()
@@ -59,6 +64,17 @@ var scanDiskCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Println(display.RenderDiskUsage(info))
+		if scanDiskSample > 0 {
+			io, err := system.SampleDiskIO(scanDiskSample)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: disk I/O unavailable: %v\n", err)
+			} else {
+				info.AttachIO(io)
+			}
+		}
+
+		printResult("disks", info, func() string {
+			return display.RenderDiskUsage(info)
+		})
 	},
 }
@@ -0,0 +1,90 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+var highStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FF6B6B"))
+
+var auditSeverities = []system.Severity{
+	system.SeverityCritical,
+	system.SeverityHigh,
+	system.SeverityMedium,
+	system.SeverityLow,
+}
+
+func FormatAuditReport(report *system.AuditReport) string {
+	header := portHeaderStyle.Render("◈ PORT AUDIT")
+	scanned := portLabelStyle.Render(fmt.Sprintf("%d listening sockets checked", report.Scanned))
+
+	if len(report.Findings) == 0 {
+		return borderStyle.Render(header + "  " + scanned + "\n\n" +
+			successStyle.Render("✓ Nothing exposed beyond localhost"))
+	}
+
+	var content string
+	content += header + "  " + scanned + "\n"
+	content += formatSeverityCounts(report.Counts) + "\n"
+
+	findingPorts := make([]system.PortInfo, len(report.Findings))
+	for i, f := range report.Findings {
+		findingPorts[i] = f.Port
+	}
+	addressWidth := bindAddressWidth(findingPorts)
+
+	for _, f := range report.Findings {
+		content += "\n"
+		content += fmt.Sprintf("  %s  %s\n",
+			getSeverityStyle(f.Severity).Render(fmt.Sprintf("%-8s", strings.ToUpper(string(f.Severity)))),
+			f.Title,
+		)
+		content += fmt.Sprintf("            %s %s  %s  %s  %s\n",
+			formatProtocol(f.Port.Protocol),
+			getPortTypeColor(f.Port.Port).Render(fmt.Sprintf("%5d", f.Port.Port)),
+			formatBindAddress(f.Port, addressWidth),
+			portLabelStyle.Render(fmt.Sprintf("[PID: %d]", f.Port.PID)),
+			portLabelStyle.Render(formatOwner(f.Port)),
+		)
+		content += lipgloss.NewStyle().MarginLeft(12).Render(labelStyle.Width(72).Render(f.Explanation)) + "\n"
+	}
+
+	return borderStyle.Render(strings.TrimRight(content, "\n"))
+}
+
+func formatSeverityCounts(counts map[system.Severity]int) string {
+	var parts []string
+	for _, s := range auditSeverities {
+		if counts[s] == 0 {
+			continue
+		}
+		parts = append(parts, getSeverityStyle(s).Render(fmt.Sprintf("%s %d", s, counts[s])))
+	}
+	return strings.Join(parts, labelStyle.Render("  •  "))
+}
+
+func getSeverityStyle(s system.Severity) lipgloss.Style {
+	switch s {
+	case system.SeverityCritical:
+		return criticalStyle
+	case system.SeverityHigh:
+		return highStyle
+	case system.SeverityMedium:
+		return warningStyle
+	}
+	return labelStyle
+}
+
+func formatOwner(p system.PortInfo) string {
+	switch {
+	case p.User != "":
+		return "user " + p.User
+	case p.UID >= 0:
+		return fmt.Sprintf("uid %d", p.UID)
+	}
+	return "user unknown"
+}
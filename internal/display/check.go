@@ -0,0 +1,50 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatCheckReport(report *system.CheckReport) string {
+	var content string
+	failed := 0
+
+	for _, r := range report.Results {
+		var icon string
+		switch r.Status {
+		case system.CheckFail:
+			icon = criticalStyle.Render("✗")
+			failed++
+		case system.CheckWarn:
+			icon = warningStyle.Render("!")
+		default:
+			icon = normalStyle.Render("✓")
+		}
+
+		content += fmt.Sprintf("%s %s %s  %s\n",
+			icon,
+			labelStyle.Render(fmt.Sprintf("%-14s", r.Check)),
+			fmt.Sprintf("%-10s", r.Target),
+			formatCheckMessage(r),
+		)
+	}
+
+	if failed == 0 {
+		content += successStyle.Render(fmt.Sprintf("✓ All %d checks passed", len(report.Results)))
+	} else {
+		content += errorStyle.Render(fmt.Sprintf("✗ %d of %d checks failed", failed, len(report.Results)))
+	}
+
+	return content
+}
+
+func formatCheckMessage(r system.CheckResult) string {
+	switch r.Status {
+	case system.CheckFail:
+		return criticalStyle.Render(r.Message)
+	case system.CheckWarn:
+		return warningStyle.Render(r.Message)
+	}
+	return r.Message
+}
@@ -0,0 +1,74 @@
+package display
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatConnectionsList(report *system.ConnectionsReport) string {
+	header := portHeaderStyle.Render("◈ CONNECTIONS")
+
+	if len(report.Connections) == 0 {
+		return borderStyle.Render(header + "\n" + "  No matching connections")
+	}
+
+	var content string
+	content += header + "\n"
+	content += formatStateCounts(report.StateCounts) + "\n\n"
+
+	for _, conn := range report.Connections {
+		content += fmt.Sprintf("  %s  %s  %-24s → %-24s  %s  %s\n",
+			formatProtocol(conn.Protocol),
+			getStateStyle(conn.State).Render(fmt.Sprintf("%-11s", conn.State)),
+			hostPort(conn.LocalAddr, conn.LocalPort),
+			hostPort(conn.RemoteAddr, conn.RemotePort),
+			portProcessStyle.Render(truncate(conn.ProcessName, 20)),
+			portLabelStyle.Render(fmt.Sprintf("[PID: %d]", conn.PID)),
+		)
+	}
+
+	return borderStyle.Render(content)
+}
+
+func formatStateCounts(counts map[string]int) string {
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	var line string
+	for i, state := range states {
+		if i > 0 {
+			line += labelStyle.Render("  •  ")
+		}
+		line += getStateStyle(state).Render(fmt.Sprintf("%s %d", state, counts[state]))
+	}
+	return line
+}
+
+// getStateStyle highlights states that usually mean trouble: CLOSE_WAIT piling
+// up is an application not closing sockets, SYN_SENT is a peer not answering.
+func getStateStyle(state string) lipgloss.Style {
+	switch state {
+	case "ESTABLISHED", "LISTEN":
+		return normalStyle
+	case "CLOSE_WAIT":
+		return criticalStyle
+	case "SYN_SENT", "SYN_RECV", "LAST_ACK", "FIN_WAIT1", "FIN_WAIT2", "CLOSING":
+		return warningStyle
+	}
+	return labelStyle
+}
+
+func hostPort(addr string, port int) string {
+	if addr == "" {
+		addr = "*"
+	}
+	return net.JoinHostPort(addr, strconv.Itoa(port))
+}
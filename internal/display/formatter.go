@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -46,40 +47,130 @@ var (
 
 	processStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
+
+	cacheStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#3B82F6"))
 )
 
-func FormatSystemOverview(
-	diskInfo *system.DiskInfo,
-	memInfo *system.MemoryInfo,
-	cpuPercent float64,
-	topProcs []system.ProcessInfo,
-) string {
-	return FormatSystemOverviewWithTime(diskInfo, memInfo, cpuPercent, topProcs, time.Time{})
-}
-
-func FormatSystemOverviewWithTime(
-	diskInfo *system.DiskInfo,
-	memInfo *system.MemoryInfo,
-	cpuPercent float64,
-	topProcs []system.ProcessInfo,
-	timestamp time.Time,
-) string {
+// OverviewOptions controls optional parts of the system overview.
+type OverviewOptions struct {
+	// Timestamp is shown in the header when set (live mode).
+	Timestamp time.Time
+	// CPUDetail adds per-core bars and the CPU time split.
+	CPUDetail bool
+	// RxHistory and TxHistory, when set, are drawn as sparklines next to
+	// the network rates (live mode).
+	RxHistory []float64
+	TxHistory []float64
+}
+
+func FormatSystemOverviewWithOptions(overview *system.Overview, opts OverviewOptions) string {
 	var content string
 
 	header := "SYSTEM OVERVIEW"
-	if !timestamp.IsZero() {
-		header += fmt.Sprintf("  (Last updated: %s)", timestamp.Format("15:04:05"))
+	if !opts.Timestamp.IsZero() {
+		header += fmt.Sprintf("  (Last updated: %s)", opts.Timestamp.Format("15:04:05"))
 	}
 	content += titleStyle.Render(header) + "\n\n"
 
-	content += formatMetricsSection(diskInfo, memInfo, cpuPercent)
+	content += FormatMetrics(overview, opts)
 	content += "\n\n"
-	content += formatProcessSection(topProcs)
+	if overview.GroupBy != system.GroupByNone {
+		content += formatGroupSection(overview.TopGroups, overview.GroupBy)
+	} else {
+		content += formatProcessSection(overview.TopProcesses)
+	}
 
 	return borderStyle.Render(content)
 }
 
-func formatMetricsSection(diskInfo *system.DiskInfo, memInfo *system.MemoryInfo, cpuPercent float64) string {
+// FormatMetrics renders the disk/memory/CPU bars without the surrounding box,
+// for embedding in other views such as the live dashboard.
+func FormatMetrics(overview *system.Overview, opts OverviewOptions) string {
+	lines := formatMetricsSection(overview.Disk, overview.Memory, overview.CPU)
+	if overview.Cgroup != nil {
+		lines += "\n" + formatCgroupLimits(overview.Cgroup)
+	}
+	if overview.Pressure != nil {
+		lines += "\n" + formatPressure(overview.Pressure)
+	}
+	if opts.CPUDetail {
+		lines += "\n" + formatCPUDetail(overview.CPU)
+	}
+	if overview.Network != nil {
+		lines += "\n" + formatNetworkLine(overview.Network, opts.RxHistory, opts.TxHistory)
+	}
+	return lines
+}
+
+func formatNetworkLine(netInfo *system.NetworkInfo, rxHistory, txHistory []float64) string {
+	rx := "↓ " + formatRate(netInfo.RxBytesPerSec)
+	tx := "↑ " + formatRate(netInfo.TxBytesPerSec)
+
+	if len(rxHistory) > 0 {
+		rx = createSparkline(rxHistory, 12) + " " + rx
+	}
+	if len(txHistory) > 0 {
+		tx = createSparkline(txHistory, 12) + " " + tx
+	}
+
+	return fmt.Sprintf("⇅ Network %s  %s", rx, tx)
+}
+
+func formatDiskIO(io *system.DiskIO) string {
+	return fmt.Sprintf("R %s  W %s  %s  %s  %s",
+		formatRate(io.ReadBytesPerSec),
+		formatRate(io.WriteBytesPerSec),
+		labelStyle.Render(fmt.Sprintf("%.0f IOPS", io.ReadIOPS+io.WriteIOPS)),
+		labelStyle.Render("await ")+getAwaitStyle(io.AwaitMs).Render(fmt.Sprintf("%.1fms", io.AwaitMs)),
+		labelStyle.Render("util ")+getColoredPercent(io.UtilPercent),
+	)
+}
+
+// getAwaitStyle flags slow I/O: beyond ~10ms even spinning disks are
+// struggling, beyond 50ms the device is saturated.
+func getAwaitStyle(ms float64) lipgloss.Style {
+	if ms >= 50 {
+		return criticalStyle
+	} else if ms >= 10 {
+		return warningStyle
+	}
+	return normalStyle
+}
+
+func formatRate(bytesPerSec float64) string {
+	return humanize.IBytes(uint64(bytesPerSec)) + "/s"
+}
+
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+// createSparkline draws the last width values scaled to their own maximum,
+// left-padded so the bar stays the same width while history fills up.
+func createSparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	maxValue := 0.0
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+
+	var spark []rune
+	for _, v := range values {
+		level := 0
+		if maxValue > 0 {
+			level = int(v / maxValue * float64(len(sparkChars)-1))
+		}
+		spark = append(spark, sparkChars[level])
+	}
+
+	return barEmpty.Render(strings.Repeat(" ", width-len(values))) + cacheStyle.Render(string(spark))
+}
+
+func formatMetricsSection(diskInfo *system.DiskInfo, memInfo *system.MemoryInfo, cpuInfo *system.CPUInfo) string {
 	var lines string
 
 	// Disk
@@ -97,31 +188,164 @@ func formatMetricsSection(diskInfo *system.DiskInfo, memInfo *system.MemoryInfo,
 			diskUsed,
 			diskTotal,
 		)
+
+		if disk.IO != nil {
+			lines += "⇵ Disk IO " + formatDiskIO(disk.IO) + "\n"
+		}
 	}
 
 	// Memory
 	memPercent := memInfo.UsedPercent
 	memUsed := humanize.IBytes(memInfo.Used)
 	memTotal := humanize.IBytes(memInfo.Total)
-	memBar := createProgressBar(memPercent, 20)
+	memBar := createMemoryBar(memInfo, 20)
 	memPercStr := getColoredPercent(memPercent)
 
-	lines += fmt.Sprintf("▣ Memory  %s %s  %s / %s\n",
+	lines += fmt.Sprintf("▣ Memory  %s %s  %s / %s  %s\n",
 		memBar,
 		memPercStr,
 		memUsed,
 		memTotal,
+		cacheStyle.Render("+"+humanize.IBytes(memInfo.Buffers+memInfo.Cached)+" cache"),
 	)
 
+	// Swap
+	lines += "⇄ Swap    " + formatSwap(memInfo.Swap) + "\n"
+
 	// CPU
-	cpuBar := createProgressBar(cpuPercent, 20)
-	cpuPercStr := getColoredPercent(cpuPercent)
+	cpuBar := createProgressBar(cpuInfo.Percent, 20)
+	cpuPercStr := getColoredPercent(cpuInfo.Percent)
 
-	lines += fmt.Sprintf("△ CPU     %s %s",
+	lines += fmt.Sprintf("△ CPU     %s %s  %s",
 		cpuBar,
 		cpuPercStr,
+		formatLoadAverage(cpuInfo),
+	)
+
+	return lines
+}
+
+// formatCgroupLimits shows usage against the limits of the cgroup csys runs
+// in, which inside a container matter more than the host totals above.
+func formatCgroupLimits(l *system.CgroupLimits) string {
+	var rows []string
+
+	if l.MemoryLimit > 0 {
+		row := fmt.Sprintf("memory %s %s  %s / %s",
+			createProgressBar(l.MemoryPercent, 10),
+			getColoredPercent(l.MemoryPercent),
+			humanize.IBytes(l.MemoryUsage),
+			humanize.IBytes(l.MemoryLimit),
+		)
+		rows = append(rows, row+"  "+formatOOMKills(l.OOMKills))
+	} else if l.OOMKills > 0 {
+		rows = append(rows, "memory unlimited  "+formatOOMKills(l.OOMKills))
+	}
+	if l.CPULimit > 0 {
+		rows = append(rows, fmt.Sprintf("cpu    %s %s  %.2f / %.2f cores",
+			createProgressBar(l.CPUPercent, 10),
+			getColoredPercent(l.CPUPercent),
+			l.CPUUsage,
+			l.CPULimit,
+		))
+	}
+	if l.PIDsLimit > 0 {
+		percent := float64(l.PIDsCurrent) / float64(l.PIDsLimit) * 100
+		rows = append(rows, fmt.Sprintf("pids   %s %s  %d / %d",
+			createProgressBar(percent, 10),
+			getColoredPercent(percent),
+			l.PIDsCurrent,
+			l.PIDsLimit,
+		))
+	}
+
+	return "⊟ Cgroup  " + strings.Join(rows, "\n          ")
+}
+
+// formatPressure shows the share of time work stalled on CPU, memory and
+// I/O over the last 10s, 60s and 300s. CPU "full" is only meaningful for
+// cgroups, so it is left out of the system-wide view.
+func formatPressure(p *system.PressureInfo) string {
+	rows := []string{
+		"cpu    " + formatPressureStats("some", p.CPU.Some) + labelStyle.Render("  avg 10s 1m 5m"),
+		"memory " + formatPressureStats("some", p.Memory.Some),
+		"io     " + formatPressureStats("some", p.IO.Some),
+	}
+	if p.Memory.Full != nil {
+		rows[1] += "  " + formatPressureStats("full", *p.Memory.Full)
+	}
+	if p.IO.Full != nil {
+		rows[2] += "  " + formatPressureStats("full", *p.IO.Full)
+	}
+	return "⧗ Stall   " + strings.Join(rows, "\n          ")
+}
+
+func formatPressureStats(label string, s system.PressureStats) string {
+	avg := func(v float64) string {
+		return getColorForPressure(v).Render(fmt.Sprintf("%5.1f%%", v))
+	}
+	return labelStyle.Render(label+" ") + avg(s.Avg10) + " " + avg(s.Avg60) + " " + avg(s.Avg300)
+}
+
+func formatOOMKills(n uint64) string {
+	text := fmt.Sprintf("OOM kills %d", n)
+	if n > 0 {
+		return criticalStyle.Render(text)
+	}
+	return labelStyle.Render(text)
+}
+
+func formatLoadAverage(cpuInfo *system.CPUInfo) string {
+	l := cpuInfo.Load
+	loadStr := func(v, perCore float64) string {
+		return getColorForPercent(perCore * 100).Render(fmt.Sprintf("%.2f", v))
+	}
+
+	cores := fmt.Sprintf(" (%d cores)", cpuInfo.LogicalCores)
+	if cpuInfo.LogicalCores == 1 {
+		cores = " (1 core)"
+	}
+
+	return labelStyle.Render("load ") +
+		loadStr(l.Load1, l.Load1PerCore) + " " +
+		loadStr(l.Load5, l.Load5PerCore) + " " +
+		loadStr(l.Load15, l.Load15PerCore) +
+		labelStyle.Render(cores)
+}
+
+func formatCPUDetail(cpuInfo *system.CPUInfo) string {
+	var lines string
+
+	t := cpuInfo.Times
+	lines += fmt.Sprintf("          %s %s  %s %s  %s %s  %s %s  %s %s\n",
+		labelStyle.Render("user"), getColoredPercent(t.User+t.Nice),
+		labelStyle.Render("sys"), getColoredPercent(t.System+t.IRQ+t.SoftIRQ),
+		labelStyle.Render("iowait"), getColoredPercent(t.IOWait),
+		labelStyle.Render("steal"), getColoredPercent(t.Steal),
+		labelStyle.Render("idle"), labelStyle.Render(fmt.Sprintf("%.0f%%", t.Idle)),
 	)
 
+	l := cpuInfo.Load
+	lines += fmt.Sprintf("          %s %s %s %s",
+		labelStyle.Render("load/core"),
+		getColoredPercent(l.Load1PerCore*100),
+		getColoredPercent(l.Load5PerCore*100),
+		getColoredPercent(l.Load15PerCore*100),
+	)
+
+	// Two cores per line keeps the box narrow on big machines.
+	const perLine = 2
+	for i, percent := range cpuInfo.PerCore {
+		if i%perLine == 0 {
+			lines += "\n         "
+		}
+		lines += fmt.Sprintf(" %s %s %s",
+			labelStyle.Render(fmt.Sprintf("%-6s", fmt.Sprintf("cpu%d", i))),
+			createProgressBar(percent, 10),
+			getColorForPercent(percent).Render(fmt.Sprintf("%3.0f%%", percent)),
+		)
+	}
+
 	return lines
 }
 
@@ -148,6 +372,31 @@ func formatProcessSection(procs []system.ProcessInfo) string {
 	return header + processes
 }
 
+// formatGroupSection replaces the process list when processes are grouped,
+// so one application's many processes take a single line.
+func formatGroupSection(groups []system.ProcessGroup, key system.ProcessGroupKey) string {
+	header := titleStyle.Render(fmt.Sprintf("▲ TOP MEMORY GROUPS (by %s)", key)) + "\n"
+
+	if len(groups) == 0 {
+		return header + "  No processes found"
+	}
+
+	var content string
+	for i, g := range groups {
+		if i >= 5 {
+			break
+		}
+		content += fmt.Sprintf("  %d  %s  %s  %s\n",
+			i+1,
+			processStyle.Render(TruncateLeft(g.Key, 35)),
+			normalStyle.Render(humanize.IBytes(g.Memory)),
+			labelStyle.Render(fmt.Sprintf("%d %s", g.Processes, plural(g.Processes, "process", "processes"))),
+		)
+	}
+
+	return header + content
+}
+
 func createProgressBar(percent float64, width int) string {
 	if percent > 100 {
 		percent = 100
@@ -159,14 +408,7 @@ func createProgressBar(percent float64, width int) string {
 	filled := int(float64(width) * percent / 100)
 	empty := width - filled
 
-	var barStyle lipgloss.Style
-	if percent >= 90 {
-		barStyle = barCritical
-	} else if percent >= 70 {
-		barStyle = barWarning
-	} else {
-		barStyle = barFilled
-	}
+	barStyle := getBarStyle(percent)
 
 	bar := ""
 	for i := 0; i < filled; i++ {
@@ -183,15 +425,96 @@ func createProgressBar(percent float64, width int) string {
 	return filledPart + emptyPart
 }
 
+type barSegment struct {
+	percent float64
+	style   lipgloss.Style
+}
+
+// createStackedBar draws segments left to right and fills the rest with the
+// empty bar character.
+func createStackedBar(width int, segments ...barSegment) string {
+	var bar string
+	used := 0
+
+	for _, seg := range segments {
+		cells := int(float64(width) * seg.percent / 100)
+		if cells < 0 {
+			cells = 0
+		}
+		if used+cells > width {
+			cells = width - used
+		}
+		bar += seg.style.Render(strings.Repeat("█", cells))
+		used += cells
+	}
+
+	return bar + barEmpty.Render(strings.Repeat("░", width-used))
+}
+
+// createMemoryBar stacks used memory (coloured by usage) and buffers/cache
+// (blue), so page-cache pressure is visible next to real usage.
+func createMemoryBar(memInfo *system.MemoryInfo, width int) string {
+	if memInfo.Total == 0 {
+		return createProgressBar(0, width)
+	}
+
+	cachePercent := float64(memInfo.Buffers+memInfo.Cached) / float64(memInfo.Total) * 100
+
+	return createStackedBar(width,
+		barSegment{percent: memInfo.UsedPercent, style: getBarStyle(memInfo.UsedPercent)},
+		barSegment{percent: cachePercent, style: cacheStyle},
+	)
+}
+
+func formatSwap(swap system.SwapInfo) string {
+	if swap.Total == 0 {
+		return labelStyle.Render("none")
+	}
+
+	return fmt.Sprintf("%s %s  %s / %s",
+		createProgressBar(swap.UsedPercent, 20),
+		getColoredPercent(swap.UsedPercent),
+		humanize.IBytes(swap.Used),
+		humanize.IBytes(swap.Total),
+	)
+}
+
+// thresholds decide when bars and percentages turn yellow and red.
+var thresholds = system.DefaultThresholds
+
+func SetThresholds(t system.Thresholds) {
+	thresholds = t
+}
+
+func getBarStyle(percent float64) lipgloss.Style {
+	if percent >= thresholds.Critical {
+		return barCritical
+	} else if percent >= thresholds.Warning {
+		return barWarning
+	}
+	return barFilled
+}
+
 func getColoredPercent(percent float64) string {
 	color := getColorForPercent(percent)
 	return color.Render(fmt.Sprintf("%.0f%%", percent))
 }
 
 func getColorForPercent(percent float64) lipgloss.Style {
-	if percent >= 90 {
+	if percent >= thresholds.Critical {
 		return criticalStyle
-	} else if percent >= 70 {
+	} else if percent >= thresholds.Warning {
+		return warningStyle
+	}
+	return normalStyle
+}
+
+// getColorForPressure is getColorForPercent for stall percentages, which
+// call for much lower thresholds than utilisation.
+func getColorForPressure(percent float64) lipgloss.Style {
+	if percent >= system.PressureThresholds.Critical {
+		return criticalStyle
+	} else if percent >= system.PressureThresholds.Warning {
 		return warningStyle
 	}
 	return normalStyle
@@ -203,3 +526,33 @@ func truncate(s string, maxLen int) string {
 	}
 	return s
 }
+
+func FormatSessionSummary(summary system.SessionSummary) string {
+	var content string
+
+	content += titleStyle.Render("SESSION SUMMARY") + "\n"
+	content += labelStyle.Render(fmt.Sprintf("%d samples over %s",
+		summary.Samples,
+		summary.Duration.Round(100*time.Millisecond),
+	)) + "\n\n"
+
+	content += labelStyle.Render(fmt.Sprintf("          %6s  %6s  %6s", "MIN", "AVG", "MAX")) + "\n"
+	content += formatSummaryLine("◉ Disk  ", summary.Disk) + "\n"
+	content += formatSummaryLine("▣ Memory", summary.Memory) + "\n"
+	content += formatSummaryLine("△ CPU   ", summary.CPU)
+
+	return borderStyle.Render(content)
+}
+
+func formatSummaryLine(label string, m system.MetricSummary) string {
+	return fmt.Sprintf("%s  %s  %s  %s",
+		label,
+		padPercent(m.Min),
+		padPercent(m.Avg),
+		padPercent(m.Max),
+	)
+}
+
+func padPercent(percent float64) string {
+	return getColorForPercent(percent).Render(fmt.Sprintf("%5.1f%%", percent))
+}
@@ -6,40 +6,256 @@ const (
 
 Quick Start:
   csys              System overview
-  csys --live       Live monitoring
+  csys --cpu-detail Per-core CPU, load and time split
+  csys --group-by name  Top memory by application, not process
+  csys --live       Live dashboard (q to quit)
+  csys -i 500ms -n 60  Sample 60 times at 500ms, then summarize
+  csys procs        Top processes by CPU
+  csys proc 4312    Everything about one process
+  csys mem          Memory and swap breakdown
+  csys net          Network interfaces and throughput
   csys scan         Scan current directory
   csys scan disk    Scan all disk partitions
+  csys check        Health check with CI exit codes
   csys ports        List listening ports
   csys ports kill   Kill process on port
-  csys ports -h     Help for ports command`
+  csys ports -h     Help for ports command
+  csys -o json      Machine-readable output (json, yaml)
+
+Inside a container or a cgroup with limits, the overview adds a Cgroup line
+with memory, CPU and PID usage against those limits and the OOM kill count;
+the Memory and CPU lines above it stay host-wide.
+
+On Linux with pressure stall information (PSI), the Stall line shows the
+share of time tasks waited on CPU, memory and I/O over 10s, 1m and 5m:
+"some" when at least one task stalled, "full" when all of them did. It
+turns yellow from 10% and red from 30%.`
 
 	PortsShort = "Manage and monitor network ports"
 	PortsLong  = `List listening ports or terminate processes.
 
 EXAMPLES:
   csys ports              List all ports
+  csys ports conns        List connections by state
+  csys ports audit        Flag services exposed beyond localhost
+  csys ports wait 5432    Block until a port is listening
+  csys ports free         Find an unused port
   csys ports kill 3000              Kill single port
   csys ports kill 3000 8080         Kill multiple ports (space-separated)
-  csys ports kill 3000 --force      Force kill without confirmation`
+  csys ports kill 3000-3010         Kill a port range
+  csys ports kill --name node       Kill by process name
+  csys ports kill 3000 -y -s KILL   Kill immediately without confirmation`
 
 	ListShort = "List all listening ports with process info"
-	ListLong  = `Display all listening ports with port number, protocol, process name, PID, memory.
+	ListLong  = `Display all listening TCP and bound UDP ports with port number, protocol,
+bind address, process name, PID, memory.
+
+Each protocol, address family and bind address is listed separately, so a
+service on 127.0.0.1 (local only) is distinguishable from one on 0.0.0.0 or
+:: (every interface).
+
+Ports held by a container show its name; a docker-proxy publishing a port
+shows "→ ⬡ name" for the container it forwards to. Containers are found from
+/proc/<pid>/cgroup and the docker, containerd, podman and CRI-O state on
+disk, without contacting a daemon; where that state is unreadable (usually
+without root) the short container ID is shown.
 
 EXAMPLES:
   csys ports list
-  csys ports              (shorthand)`
+  csys ports              (shorthand)
+  csys ports list --range 8000-9000`
+
+	ConnsShort = "List established, TIME_WAIT, CLOSE_WAIT and other connections"
+	ConnsLong  = `List TCP/UDP connections with local and remote address, owning process and
+a count per state. Listening sockets are left out unless --state all is given.
+
+EXAMPLES:
+  csys ports conns                          Every non-listening connection
+  csys ports conns --state close_wait       Hunt for CLOSE_WAIT leaks
+  csys ports conns -s established -p 5432   Who is connected to Postgres
+  csys ports conns --state all              Include listeners`
 
-	KillShort = "Kill process(es) running on specific port(s)"
-	KillLong  = `Terminate process(es) on one or more ports.
+	AuditShort = "Flag listening ports exposed to the network"
+	AuditLong  = `Check every listening socket for network exposure and rank the findings
+by severity:
 
-By default: SIGTERM → wait 1s → SIGKILL if needed
-Use --force (-f) to skip confirmation and force kill immediately.
+  critical  Database or cache port (5432, 3306, 6379, 27017, 9200...) reachable
+            beyond localhost
+  high      Process running as root accepting connections from the network
+  medium    Service bound to every interface (0.0.0.0 or ::), or a privileged
+            port (<1024) held by a non-root user
+  low       Service bound to a specific non-loopback address, or a root
+            process listening on loopback only
+
+Ownership needs permission to inspect other users' processes; run with sudo
+for a complete picture.
+
+EXAMPLES:
+  csys ports audit
+  csys ports audit -o json | jq '.data.findings[] | select(.severity == "critical")'`
+
+	WaitShort = "Wait until ports are listening or free"
+	WaitLong  = `Block until every given port is listening (or, with --state free, nothing
+is listening on it any more). Exits 0 once all ports are ready, 1 on timeout or
+interrupt and 2 on invalid arguments or if the socket table could not be read.
+
+Ports are looked up in the local socket table, counting only --protocol
+sockets (TCP by default). With --host, readiness is a TCP connect to host:port
+instead, which also works for remote hosts and ports published by containers.
+
+EXAMPLES:
+  csys ports wait 5432                       Wait up to 30s for Postgres
+  csys ports wait 3000 8080 --timeout 2m     Wait for several services
+  csys ports wait 3000 --state free          Wait for a port to be released
+  csys ports wait 6379 --host 127.0.0.1      Wait until Redis accepts connections
+  csys ports wait 53 --protocol udp          Wait for a DNS server`
+
+	FreeShort = "Find ports nothing is listening on"
+	FreeLong  = `Return unused ports, lowest first. A port counts as free when it is not in
+the socket table for --protocol and can be bound on every interface.
+
+The default range, 1024-49151, needs no root and stays clear of the kernel's
+ephemeral ports used for outgoing connections.
+
+EXAMPLES:
+  csys ports free                              One free TCP port
+  csys ports free --range 3000-3999 --count 3  Three free ports for local services
+  csys ports free --protocol udp               A free UDP port
+  csys ports free -o json | jq '.data.ports[0]'`
+
+	KillShort = "Kill process(es) on ports, port ranges or by name"
+	KillLong  = `Terminate the processes listening on the selected ports.
+
+Ports are selected by number or range, by process name, or with --all-dev
+(everything you own on ports 1024-65535). When several selectors are given a
+port must match all of them. Every target is listed, grouped by process, in a
+single confirmation before anything is signalled.
+
+By default: SIGTERM → wait up to --grace (5s) for exit → SIGKILL if needed.
+Use --signal to send something else first and --grace to give slow shutdowns
+(databases, JVMs) more time. The result shows which signal the process
+exited on. --yes (-y) skips the confirmation.
+
+Each process is pinned by start time and executable when it is listed; if the
+PID has since been reused by another process it is not signalled. On Linux the
+process is signalled and waited on through a pidfd.
+
+--tree also kills every child of the port owner (watchers, workers);
+--group kills its whole process group, including the shell or supervisor
+(npm, make, nodemon) that would otherwise respawn it. Children are signalled
+before parents, and the tree is shown in the confirmation. csys never
+signals itself or the processes it runs under.
+
+--container stops the container a port belongs to instead of its owner,
+which for a published docker port is only docker-proxy. Every process in the
+container's cgroup is signalled the same way, init last; the runtime then
+removes the proxy. Ports not held by a container are skipped. A container
+with a restart policy may be started again by its runtime.
+
+--force (-f) is deprecated; it means --yes --signal KILL.
+
+Exits 1 if nothing matched or any target was skipped or survived; declining
+the confirmation is not an error.
 
 EXAMPLES:
   csys ports kill 3000              Kill single port (with confirmation)
   csys ports kill 3000 8080         Kill multiple ports (space-separated)
-  csys ports kill 3000 --force      Force kill without confirmation
-  csys ports kill 3000 -f           Shorthand: -f for --force`
+  csys ports kill 3000-3010         Kill everything on a port range
+  csys ports kill --name node       Kill every node process holding a port
+  csys ports kill --all-dev         Clean up after crashed dev servers
+  csys ports kill 5353 -p udp       Only match UDP sockets
+  csys ports kill 5432 --grace 30s  Give Postgres 30s to shut down
+  csys ports kill 3000 --signal INT Send SIGINT instead of SIGTERM
+  csys ports kill 3000 --tree       Kill the dev server and its workers
+  csys ports kill 3000 --group      Also kill the npm/shell that started it
+  csys ports kill 8080 --container  Stop the container behind docker-proxy
+  csys ports kill 3000 -y           No confirmation
+  csys ports kill 3000 -y -s KILL   Kill immediately`
+
+	ProcShort = "Show everything about one process"
+	ProcLong  = `Show one process in detail: command line, executable, working directory,
+user, parent chain, start time and uptime, CPU%, RSS/virtual/swap memory,
+threads, open files, sockets, nice level, cgroup and container.
+
+Give a PID, or a process name to list every match with its directory and
+command line. --env adds the environment, which can hold secrets; it is
+never shown unless asked for. Other users' processes need root for some
+fields.
+
+EXAMPLES:
+  csys proc 4312              One process
+  csys proc node              Every node process and where it was started
+  csys proc 4312 --env        Include environment variables
+  csys proc 4312 -o json`
+
+	ProcsShort = "List processes sorted by CPU, memory and more"
+	ProcsLong  = `List processes with CPU%, memory, threads, open files and start time.
+
+CPU% is measured over --sample (default 1s); 100% is one full core.
+
+--tree shows every process under its parent, with the CPU% and RSS summed
+over each subtree, so an application made of many helper processes shows
+its real cost. Siblings are sorted by the subtree totals.
+
+--group-by sums CPU%, RSS and the process count of every process sharing a
+name, executable, user or cgroup; groups sort by CPU for --sort cpu and by
+memory otherwise. --group lists the processes of one group.
+
+SORT KEYS:
+  cpu, rss (memory), vms, threads, files (open files), start (newest first), pid
+
+EXAMPLES:
+  csys procs                        Top 15 processes by CPU
+  csys procs --sort cpu --limit 20  Top 20 by CPU
+  csys procs --sort rss             Top by resident memory
+  csys procs --sort start           Most recently started
+  csys procs -l 0                   Every process
+  csys procs --tree                 Parent/child hierarchy with subtree totals
+  csys procs --tree --sort rss      Which application uses the most memory overall
+  csys procs --group-by name        Every chrome or node process as one row
+  csys procs --group-by user -s rss Memory per user
+  csys procs --group chrome         The processes behind the chrome row`
+
+	MemShort = "Show detailed memory and swap usage"
+	MemLong  = `Show memory broken down into used, buffers, cache, shared, dirty and
+writeback pages, plus slab and hugepages on Linux, and swap usage.
+
+EXAMPLES:
+  csys mem
+  csys mem -o json`
+
+	NetShort = "Show network interfaces and throughput"
+	NetLong  = `List network interfaces with addresses, MTU, link state, receive/transmit
+rates measured over --sample, and error/drop counters.
+
+EXAMPLES:
+  csys net
+  csys net --sample 5s      Average rates over 5 seconds
+  csys net -o json`
+
+	CheckShort = "Check machine health against thresholds (for CI)"
+	CheckLong  = `Evaluate disk, memory and CPU usage and port state against limits and
+exit non-zero if any check fails.
+
+Disk, memory and CPU are always checked. Each fails above its --*-max
+limit, or above --crit (default 90) when no limit is given, and warns
+at --warn (default 70). Warnings do not fail the check.
+
+--port-free and --port-listening look at TCP sockets unless --protocol
+udp is given.
+
+EXIT CODES:
+  0  all checks passed (warnings allowed)
+  1  one or more checks failed
+  2  metrics could not be collected or the flags are invalid
+
+EXAMPLES:
+  csys check
+  csys check --disk-max 90 --mem-max 85 --cpu-max 95
+  csys check --port-free 5432 --port-listening 8080
+  csys check --port-free 3000,3001 --all-disks
+  csys check --port-listening 53 --protocol udp
+  csys check -o json`
 
 	ScanShort = "Analyze directory storage usage"
 	ScanLong  = `Scan a directory to see a breakdown of file types and top space consumers.
@@ -49,5 +265,7 @@ EXAMPLES:
   csys scan --path ~/Downloads   Scan specific directory`
 
 	ScanDiskShort = "Show usage of all disk partitions"
-	ScanDiskLong  = `Scan and display storage usage for all mounted disk partitions.`
+	ScanDiskLong  = `Scan and display storage usage for all mounted disk partitions, with
+read/write throughput, IOPS, average await and utilisation per device
+measured over --sample.`
 )
@@ -0,0 +1,81 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatMemoryDetail(memInfo *system.MemoryInfo) string {
+	var content string
+
+	content += titleStyle.Render("▣ MEMORY") + "\n\n"
+	content += fmt.Sprintf("%s %s  %s / %s\n",
+		createMemoryBar(memInfo, 30),
+		getColoredPercent(memInfo.UsedPercent),
+		humanize.IBytes(memInfo.Used),
+		humanize.IBytes(memInfo.Total),
+	)
+	content += fmt.Sprintf("%s used  %s buffers/cache  %s free\n\n",
+		getBarStyle(memInfo.UsedPercent).Render("█"),
+		cacheStyle.Render("█"),
+		barEmpty.Render("░"),
+	)
+
+	content += formatMemoryRow("Used", memInfo.Used, memInfo.Total)
+	content += formatMemoryRow("Available", memInfo.Available, memInfo.Total)
+	content += formatMemoryRow("Free", memInfo.Free, memInfo.Total)
+	content += formatMemoryRow("Buffers", memInfo.Buffers, memInfo.Total)
+	content += formatMemoryRow("Cached", memInfo.Cached, memInfo.Total)
+	content += formatMemoryRow("Shared", memInfo.Shared, memInfo.Total)
+	content += formatMemoryRow("Dirty", memInfo.Dirty, memInfo.Total)
+	content += formatMemoryRow("Writeback", memInfo.WriteBack, memInfo.Total)
+	if memInfo.Slab > 0 {
+		content += formatMemoryRow("Slab", memInfo.Slab, memInfo.Total)
+	}
+	if memInfo.HugePagesTotal > 0 {
+		content += fmt.Sprintf("  %-12s %d / %d free  (%s pages)\n",
+			labelStyle.Render("Hugepages"),
+			memInfo.HugePagesFree,
+			memInfo.HugePagesTotal,
+			humanize.IBytes(memInfo.HugePageSize),
+		)
+	}
+
+	content += "\n" + titleStyle.Render("⇄ SWAP") + "\n\n"
+	swap := memInfo.Swap
+	if swap.Total == 0 {
+		content += labelStyle.Render("  No swap configured")
+		return borderStyle.Render(content)
+	}
+
+	content += fmt.Sprintf("%s %s  %s / %s\n\n",
+		createProgressBar(swap.UsedPercent, 30),
+		getColoredPercent(swap.UsedPercent),
+		humanize.IBytes(swap.Used),
+		humanize.IBytes(swap.Total),
+	)
+	content += formatMemoryRow("Used", swap.Used, swap.Total)
+	content += formatMemoryRow("Free", swap.Free, swap.Total)
+	content += fmt.Sprintf("  %-12s %s in  •  %s out (since boot)",
+		labelStyle.Render("Paged"),
+		humanize.IBytes(swap.SwappedIn),
+		humanize.IBytes(swap.SwappedOut),
+	)
+
+	return borderStyle.Render(content)
+}
+
+func formatMemoryRow(label string, value, total uint64) string {
+	percent := 0.0
+	if total > 0 {
+		percent = float64(value) / float64(total) * 100
+	}
+
+	return fmt.Sprintf("  %s %10s  %s\n",
+		labelStyle.Render(fmt.Sprintf("%-12s", label)),
+		sizeStyle.Render(humanize.IBytes(value)),
+		labelStyle.Render(fmt.Sprintf("%5.1f%%", percent)),
+	)
+}
@@ -0,0 +1,77 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatNetworkList(netInfo *system.NetworkInfo) string {
+	header := titleStyle.Render("⇅ NETWORK INTERFACES")
+
+	if len(netInfo.Interfaces) == 0 {
+		return borderStyle.Render(header + "\n" + "  No interfaces found")
+	}
+
+	var content string
+	content += header + "\n"
+	content += labelStyle.Render(fmt.Sprintf("Total ↓ %s  ↑ %s (excluding loopback)",
+		formatRate(netInfo.RxBytesPerSec),
+		formatRate(netInfo.TxBytesPerSec),
+	)) + "\n"
+
+	for _, iface := range netInfo.Interfaces {
+		content += "\n" + formatInterface(iface)
+	}
+
+	return borderStyle.Render(content)
+}
+
+func formatInterface(iface system.NetInterface) string {
+	var state string
+	switch {
+	case iface.Up && iface.Running:
+		state = normalStyle.Render("● up")
+	case iface.Up:
+		state = warningStyle.Render("◐ no carrier")
+	default:
+		state = criticalStyle.Render("○ down")
+	}
+
+	var lines string
+	lines += fmt.Sprintf("%s  %s  %s\n",
+		dirStyle.Render(iface.Name),
+		state,
+		labelStyle.Render(fmt.Sprintf("MTU %d  %s", iface.MTU, iface.HardwareAddr)),
+	)
+
+	if len(iface.Addresses) > 0 {
+		lines += "  " + fileStyle.Render(strings.Join(iface.Addresses, "  ")) + "\n"
+	}
+
+	lines += fmt.Sprintf("  ↓ %s  ↑ %s  %s\n",
+		sizeStyle.Render(fmt.Sprintf("%10s", formatRate(iface.RxBytesPerSec))),
+		sizeStyle.Render(fmt.Sprintf("%10s", formatRate(iface.TxBytesPerSec))),
+		labelStyle.Render(fmt.Sprintf("%.0f/%.0f pkt/s  total %s / %s",
+			iface.RxPacketsPerSec,
+			iface.TxPacketsPerSec,
+			humanize.IBytes(iface.RxBytes),
+			humanize.IBytes(iface.TxBytes),
+		)),
+	)
+
+	errors := iface.RxErrors + iface.TxErrors
+	drops := iface.RxDropped + iface.TxDropped
+	if errors > 0 || drops > 0 {
+		style := warningStyle
+		if iface.ErrorsPerSec > 0 || iface.DropsPerSec > 0 {
+			style = criticalStyle
+		}
+		lines += "  " + style.Render(fmt.Sprintf("errors %d  drops %d (%.1f/s)",
+			errors, drops, iface.ErrorsPerSec+iface.DropsPerSec)) + "\n"
+	}
+
+	return lines
+}
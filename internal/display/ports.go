@@ -3,6 +3,8 @@ package display
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"syscall"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
@@ -24,6 +26,9 @@ var (
 	portProcessStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
 
+	containerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#3B82F6"))
+
 	successStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#04B575")).
 			Bold(true)
@@ -41,7 +46,7 @@ func FormatPortsList(ports []system.PortInfo) string {
 		)
 	}
 
-	sort.Slice(ports, func(i, j int) bool {
+	sort.SliceStable(ports, func(i, j int) bool {
 		return ports[i].Port < ports[j].Port
 	})
 
@@ -49,60 +54,226 @@ func FormatPortsList(ports []system.PortInfo) string {
 	header := portHeaderStyle.Render("◈ LISTENING PORTS")
 	content += header + "\n\n"
 
+	addressWidth := bindAddressWidth(ports)
 	for i, port := range ports {
 		portColor := getPortTypeColor(port.Port)
 		portNum := portColor.Render(fmt.Sprintf("%5d", port.Port))
 		protocol := formatProtocol(port.Protocol)
+		address := formatBindAddress(port, addressWidth)
 		processName := portProcessStyle.Render(truncate(port.ProcessName, 25))
 		pid := portLabelStyle.Render(fmt.Sprintf("[PID: %d]", port.PID))
 		memory := normalStyle.Render(humanize.IBytes(port.Memory))
 
-		content += fmt.Sprintf("  %d  ⟳ %s  %s  %s  %s  %s\n",
+		line := fmt.Sprintf("  %d  ⟳ %s  %s  %s  %s  %s  %s",
 			i+1,
 			portNum,
 			protocol,
+			address,
 			processName,
 			pid,
 			memory,
 		)
+		if port.Container != nil {
+			line += "  " + formatContainer(port.Container)
+		}
+		content += line + "\n"
 	}
 
 	return borderStyle.Render(content)
 }
 
-func FormatKillConfirmation(portInfo *system.PortInfo) string {
-	portNum := portNumberStyle.Render(fmt.Sprintf("%d", portInfo.Port))
-	protocol := formatProtocol(portInfo.Protocol)
-	processName := portProcessStyle.Render(truncate(portInfo.ProcessName, 30))
-	pid := portLabelStyle.Render(fmt.Sprintf("[PID: %d]", portInfo.PID))
-	memory := normalStyle.Render(humanize.IBytes(portInfo.Memory))
-
+func FormatKillConfirmation(targets []system.KillTarget, opts system.KillOptions) string {
 	var content string
-	content += portHeaderStyle.Render("⚠ KILL CONFIRMATION") + "\n\n"
-	content += fmt.Sprintf("  ⟳ %s  %s  %s  %s  %s\n\n",
-		portNum,
-		protocol,
-		processName,
-		pid,
-		memory,
-	)
+	content += portHeaderStyle.Render("⚠ KILL CONFIRMATION") + "  " +
+		portLabelStyle.Render(formatKillSummary(targets)) + "\n"
+
+	var targetPorts []system.PortInfo
+	for _, t := range targets {
+		targetPorts = append(targetPorts, t.Ports...)
+	}
+	addressWidth := bindAddressWidth(targetPorts)
+
+	for _, t := range targets {
+		content += "\n" + fmt.Sprintf("  %s  %s  %s  %s\n",
+			portProcessStyle.Render(truncate(t.ProcessName, 30)),
+			portLabelStyle.Render(fmt.Sprintf("[PID: %d]", t.PID)),
+			portLabelStyle.Render(t.User),
+			formatIdentity(t.Identity),
+		)
+		if t.Container != nil {
+			content += "    " + formatContainerDetail(t.Container) + "\n"
+			if t.Container.Proxy && !t.StopContainer {
+				content += labelStyle.Render("    Only the proxy is killed; --container stops the container") + "\n"
+			}
+		}
+		for _, port := range t.Ports {
+			content += fmt.Sprintf("    ⟳ %s  %s  %s  %s\n",
+				getPortTypeColor(port.Port).Render(fmt.Sprintf("%5d", port.Port)),
+				formatProtocol(port.Protocol),
+				formatBindAddress(port, addressWidth),
+				normalStyle.Render(humanize.IBytes(port.Memory)),
+			)
+		}
+		content += formatKillTree(t)
+	}
+	content += "\n" + labelStyle.Render("  "+formatKillPlan(opts)) + "\n"
 	content += labelStyle.Render("  Confirm termination? [y/N]: ")
 
 	return borderStyle.Render(content)
 }
 
-func FormatKillSuccess(port int, processName string) string {
-	var content string
-	content += successStyle.Render(fmt.Sprintf("✓ Port %d (%s) killed successfully", port, processName))
+// formatIdentity shows what the kill is pinned to; a process whose start
+// time or executable differs by then is left alone.
+func formatIdentity(id system.ProcessIdentity) string {
+	if id.StartTime.IsZero() {
+		return warningStyle.Render("identity unknown, will be skipped")
+	}
+	text := "started " + formatStartTime(id.StartTime)
+	if id.Exe != "" {
+		text += "  " + id.Exe
+	}
+	return portLabelStyle.Render(text)
+}
+
+// formatKillTree lists the processes a --tree or --group kill reaches,
+// indented by depth. They are signalled deepest first.
+func formatKillTree(t system.KillTarget) string {
+	if len(t.Tree) == 0 {
+		return ""
+	}
+
+	title := "    Process tree, signalled bottom-up:"
+	if t.StopContainer {
+		title = fmt.Sprintf("    Processes of container %s, signalled bottom-up:", t.Container.DisplayName())
+	}
+	content := labelStyle.Render(title) + "\n"
+	for _, p := range t.Tree {
+		line := fmt.Sprintf("    %s└─ %s  %s",
+			strings.Repeat("   ", p.Depth),
+			portProcessStyle.Render(truncate(p.Identity.Name, 30)),
+			portLabelStyle.Render(fmt.Sprintf("[PID: %d]", p.Identity.PID)),
+		)
+		if p.Identity.PID == t.PID {
+			line += "  " + warningStyle.Render("← holds the port")
+		}
+		content += line + "\n"
+	}
 	return content
 }
 
-func FormatKillError(port int, err error) string {
+func formatKillSummary(targets []system.KillTarget) string {
+	ports, procs := 0, 0
+	for _, t := range targets {
+		ports += len(t.PortNumbers())
+		procs += max(len(t.Tree), 1)
+	}
+	return fmt.Sprintf("%d %s holding %d %s",
+		procs, plural(procs, "process", "processes"),
+		ports, plural(ports, "port", "ports"))
+}
+
+func formatKillPlan(opts system.KillOptions) string {
+	if opts.Signal == syscall.SIGKILL {
+		return "Sends SIGKILL"
+	}
+	return fmt.Sprintf("Sends %s, then SIGKILL if still running after %s",
+		system.SignalName(opts.Signal), opts.Grace)
+}
+
+// formatContainer is the short form for lists: the name, or an arrow to it
+// for a docker-proxy publishing its port.
+func formatContainer(c *system.ContainerInfo) string {
+	if c.Proxy {
+		return containerStyle.Render("→ ⬡ " + c.DisplayName())
+	}
+	return containerStyle.Render("⬡ " + c.DisplayName())
+}
+
+func formatContainerDetail(c *system.ContainerInfo) string {
+	text := "container " + c.DisplayName()
+	if c.Proxy {
+		text = "forwards to container " + c.DisplayName()
+	}
+	details := []string{c.Runtime, c.ShortID()}
+	if c.Image != "" {
+		details = append([]string{c.Image}, details...)
+	}
+	return containerStyle.Render("⬡ "+text) + "  " + portLabelStyle.Render(strings.Join(details, "  "))
+}
+
+func FormatKillSuccess(target system.KillTarget, result system.KillResult, opts system.KillOptions) string {
+	if result.Container != nil {
+		return formatContainerStopped(target, result, opts)
+	}
+	ports := formatPortNumbers(target.PortNumbers())
+	var line string
+	if result.Escalated {
+		line = warningStyle.Render(fmt.Sprintf("! %s [PID: %d] ignored %s for %s, killed with %s, freed %s",
+			target.ProcessName, target.PID, system.SignalName(opts.Signal), opts.Grace, result.Signal, ports))
+	} else {
+		line = successStyle.Render(fmt.Sprintf("✓ %s [PID: %d] exited on %s, freed %s",
+			target.ProcessName, target.PID, result.Signal, ports))
+	}
+	return line + formatRelatedKills(result.Related)
+}
+
+// formatContainerStopped reports a --container kill. The runtime releases
+// the ports once it sees the container exit.
+func formatContainerStopped(target system.KillTarget, result system.KillResult, opts system.KillOptions) string {
+	name := result.Container.DisplayName()
+	ports := formatPortNumbers(target.PortNumbers())
+	var line string
+	if result.Escalated {
+		line = warningStyle.Render(fmt.Sprintf("! Container %s ignored %s for %s, killed with %s (held %s)",
+			name, system.SignalName(opts.Signal), opts.Grace, result.Signal, ports))
+	} else {
+		line = successStyle.Render(fmt.Sprintf("✓ Container %s stopped: %s [PID: %d] exited on %s (held %s)",
+			name, result.ProcessName, result.PID, result.Signal, ports))
+	}
+	return line + formatRelatedKills(result.Related)
+}
+
+func formatRelatedKills(related []system.ProcessKill) string {
 	var content string
-	content += errorStyle.Render(fmt.Sprintf("✗ Failed to kill port %d: %v", port, err))
+	for _, r := range related {
+		if r.Error != "" {
+			content += "\n" + errorStyle.Render(fmt.Sprintf("  ✗ %s [PID: %d]: %s", r.ProcessName, r.PID, r.Error))
+			continue
+		}
+		content += "\n" + portLabelStyle.Render(fmt.Sprintf("  ✓ %s [PID: %d] exited on %s", r.ProcessName, r.PID, r.Signal))
+	}
 	return content
 }
 
+func FormatKillError(target system.KillTarget, result system.KillResult) string {
+	return errorStyle.Render(fmt.Sprintf("✗ Failed to kill %s: %s",
+		formatPortNumbers(target.PortNumbers()), result.Error)) + formatRelatedKills(result.Related)
+}
+
+func FormatNotInContainer(target system.KillTarget) string {
+	return errorStyle.Render(fmt.Sprintf("✗ Skipped %s: %s [PID: %d] is not in a container",
+		formatPortNumbers(target.PortNumbers()), target.ProcessName, target.PID))
+}
+
+func FormatNoKillTargets() string {
+	return errorStyle.Render("✗ No listening ports matched")
+}
+
+func formatPortNumbers(ports []int) string {
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = fmt.Sprintf("%d", p)
+	}
+	return plural(len(ports), "port ", "ports ") + strings.Join(parts, ", ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
 func FormatPortNotFound(port int) string {
 	var content string
 	content += errorStyle.Render(fmt.Sprintf("✗ Port %d is not listening", port))
@@ -116,6 +287,39 @@ func formatProtocol(proto string) string {
 	return warningStyle.Render("⚡ UDP")
 }
 
+// formatBindAddress shows where a socket accepts connections from: loopback
+// addresses are local-only, wildcards (0.0.0.0, ::) are reachable from every
+// interface. The address is padded to width, never cut: a truncated IPv6
+// address is a different address.
+func formatBindAddress(port system.PortInfo, width int) string {
+	address := fmt.Sprintf("%-*s", width, bindAddress(port))
+
+	switch {
+	case port.IsLoopback():
+		return normalStyle.Render(address)
+	case port.IsWildcard():
+		return warningStyle.Render(address)
+	}
+	return portLabelStyle.Render(address)
+}
+
+func bindAddress(port system.PortInfo) string {
+	if port.Address == "" {
+		return "*"
+	}
+	return port.Address
+}
+
+// bindAddressWidth is the width of the address column: the longest address
+// in the list.
+func bindAddressWidth(ports []system.PortInfo) int {
+	width := 0
+	for _, port := range ports {
+		width = max(width, len(bindAddress(port)))
+	}
+	return width
+}
+
 func getPortTypeColor(port int) lipgloss.Style {
 	if port < 1024 {
 		return criticalStyle
@@ -124,3 +328,26 @@ func getPortTypeColor(port int) lipgloss.Style {
 	}
 	return normalStyle
 }
+
+func FormatFreePorts(free *system.FreePorts, requested int) string {
+	header := portHeaderStyle.Render("◈ FREE PORTS") + "  " +
+		portLabelStyle.Render(fmt.Sprintf("%s %s", strings.ToUpper(free.Protocol), free.Range))
+
+	if len(free.Ports) == 0 {
+		return borderStyle.Render(header + "\n\n" + errorStyle.Render("✗ No free ports in range"))
+	}
+
+	var content string
+	content += header + "\n\n"
+	for _, port := range free.Ports {
+		content += fmt.Sprintf("  %s  %s\n",
+			successStyle.Render("✓"),
+			getPortTypeColor(port).Render(fmt.Sprintf("%5d", port)),
+		)
+	}
+	if len(free.Ports) < requested {
+		content += "\n" + warningStyle.Render(fmt.Sprintf("Only %d of %d requested ports are free", len(free.Ports), requested))
+	}
+
+	return borderStyle.Render(strings.TrimRight(content, "\n"))
+}
@@ -0,0 +1,136 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatProcessDetail(proc system.ProcessInfo) string {
+	var content string
+
+	content += titleStyle.Render("◉ "+proc.Name) + "  " +
+		portLabelStyle.Render(fmt.Sprintf("[PID: %d]", proc.PID))
+	if proc.Status != "" {
+		content += "  " + labelStyle.Render(proc.Status)
+	}
+	content += "\n\n"
+
+	content += formatDetailRow("Command", orDash(proc.Cmdline))
+	content += formatDetailRow("Executable", orDash(proc.Exe))
+	content += formatDetailRow("Directory", orDash(proc.Cwd))
+	content += formatDetailRow("User", orDash(proc.User))
+	content += formatDetailRow("Parents", formatAncestors(proc.Ancestors))
+	content += formatDetailRow("Started", formatUptime(proc.StartTime))
+	nice := ""
+	if proc.Nice != nil {
+		nice = fmt.Sprintf("%d", *proc.Nice)
+	}
+	content += formatDetailRow("Nice", orDash(nice))
+	if proc.Cgroup != "" {
+		content += formatDetailRow("Cgroup", proc.Cgroup)
+	}
+	if proc.Container != nil {
+		content += formatDetailRow("Container", formatContainerDetail(proc.Container))
+	}
+
+	content += "\n"
+	content += formatDetailRow("CPU", getColorForPercent(proc.CPUPercent).Render(fmt.Sprintf("%.1f%%", proc.CPUPercent)))
+	content += formatDetailRow("Memory", fmt.Sprintf("%s RSS  •  %s virtual  •  %s swap",
+		normalStyle.Render(humanize.IBytes(proc.Memory)),
+		humanize.IBytes(proc.VMS),
+		humanize.IBytes(proc.Swap),
+	))
+	content += formatDetailRow("Threads", fmt.Sprintf("%d", proc.Threads))
+	content += formatDetailRow("Open files", fmt.Sprintf("%d", proc.OpenFiles))
+
+	content += "\n" + portHeaderStyle.Render("◈ SOCKETS") + "\n"
+	if len(proc.Sockets) == 0 {
+		content += labelStyle.Render("  None")
+	}
+	for _, s := range proc.Sockets {
+		line := fmt.Sprintf("  %s  %s  %s",
+			formatProtocol(s.Protocol),
+			getStateStyle(s.State).Render(fmt.Sprintf("%-11s", s.State)),
+			hostPort(s.LocalAddr, s.LocalPort),
+		)
+		if s.RemotePort != 0 {
+			line += " → " + hostPort(s.RemoteAddr, s.RemotePort)
+		}
+		content += line + "\n"
+	}
+
+	if len(proc.Env) > 0 {
+		content = strings.TrimRight(content, "\n") + "\n\n" + titleStyle.Render("⚙ ENVIRONMENT") + "\n"
+		for _, kv := range proc.Env {
+			key, value, _ := strings.Cut(kv, "=")
+			content += "  " + labelStyle.Render(key+"=") + value + "\n"
+		}
+	}
+
+	return borderStyle.Render(strings.TrimRight(content, "\n"))
+}
+
+// FormatProcessMatches lists every process a name matched, with enough
+// context (directory, command line) to pick the right PID.
+func FormatProcessMatches(query string, procs []system.ProcessInfo) string {
+	var content string
+	content += titleStyle.Render(fmt.Sprintf("◉ %d processes named %q", len(procs), query)) + "\n\n"
+	content += labelStyle.Render(fmt.Sprintf("  %7s  %6s  %9s  %-10s  %s",
+		"PID", "CPU%", "RSS", "USER", "DIRECTORY / COMMAND")) + "\n"
+
+	for _, proc := range procs {
+		where := orDash(proc.Cwd)
+		if proc.Container != nil {
+			where += "  " + formatContainer(proc.Container)
+		}
+		content += fmt.Sprintf("  %7d  %s  %s  %-10s  %s\n",
+			proc.PID,
+			getColorForPercent(proc.CPUPercent).Render(fmt.Sprintf("%6.1f", proc.CPUPercent)),
+			normalStyle.Render(fmt.Sprintf("%9s", humanize.IBytes(proc.Memory))),
+			truncate(orDash(proc.User), 10),
+			where,
+		)
+		if proc.Cmdline != "" {
+			content += fmt.Sprintf("  %38s%s\n", "", labelStyle.Render(truncate(proc.Cmdline, 60)))
+		}
+	}
+	content += "\n" + labelStyle.Render("  csys proc <pid> for full detail")
+
+	return borderStyle.Render(content)
+}
+
+func formatDetailRow(label, value string) string {
+	return fmt.Sprintf("  %s %s\n", labelStyle.Render(fmt.Sprintf("%-11s", label)), value)
+}
+
+func formatAncestors(ancestors []system.ProcessRef) string {
+	if len(ancestors) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(ancestors))
+	for i, a := range ancestors {
+		parts[i] = fmt.Sprintf("%s %s", a.Name, portLabelStyle.Render(fmt.Sprintf("[%d]", a.PID)))
+	}
+	return strings.Join(parts, labelStyle.Render(" ← "))
+}
+
+func formatUptime(start time.Time) string {
+	if start.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s  %s",
+		start.Format("2006-01-02 15:04:05"),
+		labelStyle.Render("(up "+time.Since(start).Round(time.Second).String()+")"),
+	)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
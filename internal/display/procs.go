@@ -0,0 +1,133 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatProcessList(procs []system.ProcessInfo, sortBy system.ProcessSortKey) string {
+	header := titleStyle.Render(fmt.Sprintf("▲ PROCESSES (by %s)", sortBy))
+
+	if len(procs) == 0 {
+		return borderStyle.Render(header + "\n" + "  No processes found")
+	}
+
+	containers := anyContainer(procs)
+	columns := fmt.Sprintf("  %7s  %-28s  %6s  %9s  %9s  %4s  %5s  %-8s",
+		"PID", "NAME", "CPU%", "RSS", "VMS", "THR", "FILES", "STARTED")
+	if containers {
+		columns += "  CONTAINER"
+	}
+
+	var content string
+	content += header + "\n\n"
+	content += labelStyle.Render(strings.TrimRight(columns, " ")) + "\n"
+
+	for _, proc := range procs {
+		content += fmt.Sprintf("  %7d  %s  %s  %s  %9s  %4d  %5d  %s%s\n",
+			proc.PID,
+			processStyle.Render(fmt.Sprintf("%-28s", truncate(proc.Name, 28))),
+			getColorForPercent(proc.CPUPercent).Render(fmt.Sprintf("%6.1f", proc.CPUPercent)),
+			normalStyle.Render(fmt.Sprintf("%9s", humanize.IBytes(proc.Memory))),
+			humanize.IBytes(proc.VMS),
+			proc.Threads,
+			proc.OpenFiles,
+			labelStyle.Render(fmt.Sprintf("%-8s", formatStartTime(proc.StartTime))),
+			formatContainerColumn(proc.Container, containers),
+		)
+	}
+
+	return borderStyle.Render(content)
+}
+
+// anyContainer reports whether the Container column is needed; hosts
+// without containers do not get an empty one.
+func anyContainer(procs []system.ProcessInfo) bool {
+	for _, p := range procs {
+		if p.Container != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func formatContainerColumn(c *system.ContainerInfo, shown bool) string {
+	if !shown || c == nil {
+		return ""
+	}
+	return "  " + formatContainer(c)
+}
+
+// FormatProcessGroups shows one row per group with the summed CPU% and RSS.
+// Long executable paths and cgroups keep their end, which names the program.
+func FormatProcessGroups(groups []system.ProcessGroup, key system.ProcessGroupKey, sortBy system.ProcessSortKey) string {
+	groupSort := "memory"
+	if sortBy == system.SortByCPU {
+		groupSort = "cpu"
+	}
+	header := titleStyle.Render(fmt.Sprintf("▲ PROCESS GROUPS (by %s, sorted by %s)", key, groupSort))
+
+	if len(groups) == 0 {
+		return borderStyle.Render(header + "\n" + "  No processes found")
+	}
+
+	var content string
+	content += header + "\n\n"
+	content += labelStyle.Render(fmt.Sprintf("  %-40s  %5s  %6s  %9s  %s",
+		strings.ToUpper(string(key)), "PROCS", "CPU%", "RSS", "PIDS")) + "\n"
+
+	for _, g := range groups {
+		content += fmt.Sprintf("  %s  %5d  %s  %s  %s\n",
+			processStyle.Render(fmt.Sprintf("%-40s", TruncateLeft(g.Key, 40))),
+			g.Processes,
+			getColorForPercent(g.CPUPercent).Render(fmt.Sprintf("%6.1f", g.CPUPercent)),
+			normalStyle.Render(fmt.Sprintf("%9s", humanize.IBytes(g.Memory))),
+			labelStyle.Render(formatGroupPIDs(g.PIDs, 4)),
+		)
+	}
+
+	return borderStyle.Render(content)
+}
+
+func formatGroupPIDs(pids []int32, limit int) string {
+	parts := make([]string, 0, limit+1)
+	for i, pid := range pids {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("+%d", len(pids)-limit))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d", pid))
+	}
+	return strings.Join(parts, " ")
+}
+
+// TruncateLeft keeps the end of s, where paths name the program, in at most
+// maxLen characters (never fewer than 4).
+func TruncateLeft(s string, maxLen int) string {
+	if maxLen < 4 {
+		maxLen = 4
+	}
+	r := []rune(s)
+	if len(r) > maxLen {
+		return "..." + string(r[len(r)-maxLen+3:])
+	}
+	return s
+}
+
+// formatStartTime shows the clock time for processes started today and the
+// date for older ones, like ps.
+func formatStartTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("Jan 02")
+}
@@ -0,0 +1,133 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+// ProcessTreeRow is one line of a rendered process tree.
+type ProcessTreeRow struct {
+	Node *system.ProcessNode
+	// Prefix holds the branch glyphs drawn before the name.
+	Prefix    string
+	Collapsed bool
+}
+
+// FlattenProcessTree walks roots depth-first into display rows. Children of
+// PIDs in collapsed are hidden. If match is set, only processes that match or
+// have a matching descendant are kept, so matches stay in context.
+func FlattenProcessTree(roots []*system.ProcessNode, collapsed map[int32]bool, match func(system.ProcessInfo) bool) []ProcessTreeRow {
+	var rows []ProcessTreeRow
+
+	var walk func(nodes []*system.ProcessNode, indent string, top bool)
+	walk = func(nodes []*system.ProcessNode, indent string, top bool) {
+		visible := nodes
+		if match != nil {
+			visible = nil
+			for _, n := range nodes {
+				if subtreeMatches(n, match) {
+					visible = append(visible, n)
+				}
+			}
+		}
+
+		for i, n := range visible {
+			last := i == len(visible)-1
+			prefix, childIndent := "", ""
+			if !top {
+				prefix, childIndent = indent+"├─ ", indent+"│  "
+				if last {
+					prefix, childIndent = indent+"└─ ", indent+"   "
+				}
+			}
+
+			hidden := collapsed[n.PID] && len(n.Children) > 0
+			rows = append(rows, ProcessTreeRow{Node: n, Prefix: prefix, Collapsed: hidden})
+			if !hidden {
+				walk(n.Children, childIndent, false)
+			}
+		}
+	}
+	walk(roots, "", true)
+
+	return rows
+}
+
+func subtreeMatches(n *system.ProcessNode, match func(system.ProcessInfo) bool) bool {
+	if match(n.ProcessInfo) {
+		return true
+	}
+	for _, c := range n.Children {
+		if subtreeMatches(c, match) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatProcessTree renders the hierarchy with each subtree's summed CPU% and
+// RSS next to the process's own RSS. limit caps the rows; 0 shows all.
+func FormatProcessTree(roots []*system.ProcessNode, sortBy system.ProcessSortKey, limit int) string {
+	header := titleStyle.Render(fmt.Sprintf("▲ PROCESS TREE (by %s)", sortBy))
+
+	rows := FlattenProcessTree(roots, nil, nil)
+	if len(rows) == 0 {
+		return borderStyle.Render(header + "\n" + "  No processes found")
+	}
+
+	shown := rows
+	if limit > 0 && len(rows) > limit {
+		shown = rows[:limit]
+	}
+	containers := false
+	for _, row := range shown {
+		containers = containers || row.Node.Container != nil
+	}
+	columns := fmt.Sprintf("  %7s  %-40s  %7s  %9s  %9s  %5s", "PID", "NAME", "Σ CPU%", "Σ RSS", "RSS", "PROCS")
+	if containers {
+		columns += "  CONTAINER"
+	}
+
+	var content string
+	content += header + "\n\n"
+	content += labelStyle.Render(columns) + "\n"
+
+	for _, row := range shown {
+		n := row.Node
+		content += fmt.Sprintf("  %7d  %s%s  %s  %s  %9s  %5d%s\n",
+			n.PID,
+			labelStyle.Render(row.Prefix),
+			processStyle.Render(PadRunes(n.Name, 40-runeLen(row.Prefix))),
+			getColorForPercent(n.SubtreeCPUPercent).Render(fmt.Sprintf("%7.1f", n.SubtreeCPUPercent)),
+			normalStyle.Render(fmt.Sprintf("%9s", humanize.IBytes(n.SubtreeMemory))),
+			humanize.IBytes(n.Memory),
+			n.Descendants+1,
+			formatContainerColumn(n.Container, containers),
+		)
+	}
+	if len(shown) < len(rows) {
+		content += labelStyle.Render(fmt.Sprintf("  … %d more (--limit 0 shows all)", len(rows)-len(shown))) + "\n"
+	}
+
+	return borderStyle.Render(strings.TrimRight(content, "\n"))
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// PadRunes truncates or pads s to width characters (never fewer than 4),
+// counting runes so tree glyphs and non-ASCII names line up.
+func PadRunes(s string, width int) string {
+	if width < 4 {
+		width = 4
+	}
+	r := []rune(s)
+	if len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
@@ -139,11 +139,15 @@ func RenderDiskUsage(info *system.DiskInfo) string {
 			free := humanize.IBytes(disk.Free)
 
 			content += fmt.Sprintf("%s %s\n", bar, percStr)
-			content += fmt.Sprintf("%s used  •  %s free  •  %s total\n\n",
+			content += fmt.Sprintf("%s used  •  %s free  •  %s total\n",
 				sizeStyle.Render(used),
 				fileStyle.Render(free),
 				fileStyle.Render(total),
 			)
+			if disk.IO != nil {
+				content += "⇵ " + formatDiskIO(disk.IO) + "\n"
+			}
+			content += "\n"
 		}
 	}
 
@@ -159,11 +163,18 @@ func RenderDiskUsage(info *system.DiskInfo) string {
 			used := humanize.IBytes(disk.Used)
 			percent := getColoredPercent(disk.Percent)
 
-			content += fmt.Sprintf("  • %-30s  %s used (%s)\n",
+			content += fmt.Sprintf("  • %-30s  %s used (%s)",
 				fileStyle.Render(name),
 				sizeStyle.Render(used),
 				percent,
 			)
+			if disk.IO != nil && disk.IO.ReadBytesPerSec+disk.IO.WriteBytesPerSec > 0 {
+				content += labelStyle.Render(fmt.Sprintf("  R %s  W %s",
+					formatRate(disk.IO.ReadBytesPerSec),
+					formatRate(disk.IO.WriteBytesPerSec),
+				))
+			}
+			content += "\n"
 		}
 	}
 
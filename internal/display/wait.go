@@ -0,0 +1,27 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+func FormatPortWaitReport(report *system.PortWaitReport) string {
+	var lines []string
+	for _, r := range report.Results {
+		elapsed := r.ElapsedNS.Round(100 * time.Millisecond)
+		if r.Ready {
+			lines = append(lines, fmt.Sprintf("%s Port %s %s after %s",
+				successStyle.Render("✓"),
+				portNumberStyle.Render(fmt.Sprintf("%d", r.Port)),
+				r.State,
+				elapsed,
+			))
+			continue
+		}
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("✗ Port %d not %s after %s", r.Port, r.State, elapsed)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is bumped whenever a field in a serialized document is
+// renamed, removed or changes meaning. Adding new fields does not bump it.
+const SchemaVersion = 1
+
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+)
+
+// Document is the envelope every machine-readable result is wrapped in.
+type Document struct {
+	SchemaVersion int    `json:"schema_version"`
+	Kind          string `json:"kind"`
+	Data          any    `json:"data"`
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case Table, JSON, YAML:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid output format %q (must be table, json or yaml)", s)
+}
+
+func (f Format) IsStructured() bool {
+	return f == JSON || f == YAML
+}
+
+func Write(w io.Writer, format Format, kind string, data any) error {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Kind:          kind,
+		Data:          data,
+	}
+
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case YAML:
+		return writeYAML(w, doc)
+	}
+
+	return fmt.Errorf("format %q is not a structured format", format)
+}
+
+// writeYAML goes through JSON first so that YAML documents use exactly the
+// same field names and ordering as the json struct tags.
+func writeYAML(w io.Writer, doc Document) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+
+	// Always start a new document so live mode can stream several of them.
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// clearStyle drops the flow style yaml picks up from JSON input so the
+// document is emitted as block YAML.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,170 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	}
+	return 0
+}
+
+type AuditFinding struct {
+	Severity    Severity `json:"severity"`
+	Rule        string   `json:"rule"`
+	Title       string   `json:"title"`
+	Explanation string   `json:"explanation"`
+	Port        PortInfo `json:"port"`
+}
+
+type AuditReport struct {
+	Findings []AuditFinding   `json:"findings"`
+	Counts   map[Severity]int `json:"counts"`
+	Scanned  int              `json:"sockets_scanned"`
+}
+
+// dataStorePorts are well-known database, cache and broker ports. These
+// services often ship without authentication for local development.
+var dataStorePorts = map[int]string{
+	1433:  "SQL Server",
+	1521:  "Oracle",
+	2181:  "ZooKeeper",
+	2379:  "etcd",
+	3306:  "MySQL",
+	4222:  "NATS",
+	5432:  "PostgreSQL",
+	5672:  "RabbitMQ",
+	5984:  "CouchDB",
+	6379:  "Redis",
+	7474:  "Neo4j",
+	8086:  "InfluxDB",
+	8123:  "ClickHouse",
+	9042:  "Cassandra",
+	9092:  "Kafka",
+	9200:  "Elasticsearch",
+	9300:  "Elasticsearch transport",
+	11211: "Memcached",
+	15672: "RabbitMQ management",
+	26257: "CockroachDB",
+	27017: "MongoDB",
+	28015: "RethinkDB",
+}
+
+// AuditPorts flags listening sockets that are reachable from other machines
+// or held by unexpected users, ranked most severe first.
+func AuditPorts(ports []PortInfo) *AuditReport {
+	report := &AuditReport{
+		Findings: make([]AuditFinding, 0),
+		Counts:   make(map[Severity]int),
+		Scanned:  len(ports),
+	}
+
+	add := func(f AuditFinding) {
+		report.Findings = append(report.Findings, f)
+		report.Counts[f.Severity]++
+	}
+
+	for _, p := range ports {
+		exposed := !p.IsLoopback()
+		where := "every network interface"
+		if !p.IsWildcard() {
+			where = p.Address
+		}
+
+		service, isDataStore := dataStorePorts[p.Port]
+
+		switch {
+		case exposed && isDataStore:
+			add(AuditFinding{
+				Severity: SeverityCritical,
+				Rule:     "datastore-exposed",
+				Title:    fmt.Sprintf("%s is reachable from other machines", service),
+				Explanation: fmt.Sprintf("Port %d (%s) is bound to %s. Development databases often have no password; bind it to 127.0.0.1 instead.",
+					p.Port, service, where),
+				Port: p,
+			})
+		case exposed && p.IsWildcard():
+			add(AuditFinding{
+				Severity: SeverityMedium,
+				Rule:     "wildcard-bind",
+				Title:    fmt.Sprintf("%s listens on all interfaces", p.ProcessName),
+				Explanation: fmt.Sprintf("Port %d accepts connections from any network this machine joins, including public Wi-Fi. Bind to 127.0.0.1 or ::1 if only local access is needed.",
+					p.Port),
+				Port: p,
+			})
+		case exposed:
+			add(AuditFinding{
+				Severity: SeverityLow,
+				Rule:     "interface-bind",
+				Title:    fmt.Sprintf("%s listens on %s", p.ProcessName, p.Address),
+				Explanation: fmt.Sprintf("Port %d is reachable by other hosts on the network %s belongs to.",
+					p.Port, p.Address),
+				Port: p,
+			})
+		}
+
+		if p.UID == 0 {
+			f := AuditFinding{
+				Severity: SeverityLow,
+				Rule:     "root-listener",
+				Title:    fmt.Sprintf("%s accepts connections as root", p.ProcessName),
+				Explanation: fmt.Sprintf("A bug in the process on port %d runs with full privileges. It is only reachable locally.",
+					p.Port),
+				Port: p,
+			}
+			if exposed {
+				f.Severity = SeverityHigh
+				f.Explanation = fmt.Sprintf("A bug in the process on port %d runs with full privileges and is reachable from %s.",
+					p.Port, where)
+			}
+			add(f)
+		}
+
+		if p.Port < 1024 && p.UID > 0 {
+			add(AuditFinding{
+				Severity: SeverityMedium,
+				Rule:     "privileged-port-non-root",
+				Title:    fmt.Sprintf("Privileged port %d held by %s", p.Port, ownerName(p)),
+				Explanation: fmt.Sprintf("Ports below 1024 normally need root. %s holds it as an unprivileged user, which usually means a capability grant or a service impersonating a system one.",
+					p.ProcessName),
+				Port: p,
+			})
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Severity.rank() != b.Severity.rank() {
+			return a.Severity.rank() > b.Severity.rank()
+		}
+		return a.Port.Port < b.Port.Port
+	})
+
+	return report
+}
+
+func ownerName(p PortInfo) string {
+	if p.User != "" {
+		return p.User
+	}
+	return fmt.Sprintf("UID %d", p.UID)
+}
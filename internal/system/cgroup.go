@@ -0,0 +1,82 @@
+package system
+
+import "time"
+
+// CgroupLimits is what the cgroup csys runs in allows, next to what it
+// uses. Inside a container or a limited systemd slice these, not the host
+// totals, are what the workload runs out of. Zero limits mean unlimited.
+type CgroupLimits struct {
+	Version int    `json:"version"`
+	Path    string `json:"path"`
+
+	MemoryLimit uint64 `json:"memory_limit_bytes"`
+	// MemoryUsage excludes inactive page cache, which the kernel reclaims
+	// before it OOM-kills; docker stats and kubectl top count it the same.
+	MemoryUsage   uint64  `json:"memory_usage_bytes"`
+	MemoryPercent float64 `json:"memory_percent"`
+	// OOMKills counts processes killed for hitting the memory limit.
+	OOMKills uint64 `json:"oom_kills"`
+
+	// CPULimit is the quota in cores (quota / period).
+	CPULimit   float64 `json:"cpu_limit_cores"`
+	CPUUsage   float64 `json:"cpu_usage_cores"`
+	CPUPercent float64 `json:"cpu_percent"`
+
+	PIDsLimit   uint64 `json:"pids_limit"`
+	PIDsCurrent uint64 `json:"pids_current"`
+}
+
+// Limited reports whether any limit applies.
+func (l *CgroupLimits) Limited() bool {
+	return l.MemoryLimit > 0 || l.CPULimit > 0 || l.PIDsLimit > 0
+}
+
+// CgroupSampler reads the current process's cgroup and computes CPU usage
+// against the quota from the usage counter between Samples.
+type CgroupSampler struct {
+	prevUsage uint64
+	prevTime  time.Time
+}
+
+func NewCgroupSampler() *CgroupSampler {
+	return &CgroupSampler{}
+}
+
+// Sample returns the cgroup's limits and usage, or nil when csys is not
+// limited by one: on other platforms, at the root cgroup, or when every
+// limit is at least the host's memory and core count.
+func (s *CgroupSampler) Sample(memTotal uint64, cores int) *CgroupLimits {
+	limits, usage, ok := readCgroupLimits()
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	if !s.prevTime.IsZero() && usage >= s.prevUsage {
+		if elapsed := now.Sub(s.prevTime).Seconds(); elapsed > 0 {
+			limits.CPUUsage = float64(usage-s.prevUsage) / 1e9 / elapsed
+		}
+	}
+	s.prevUsage = usage
+	s.prevTime = now
+
+	// A limit the host cannot reach anyway is not one.
+	if memTotal > 0 && limits.MemoryLimit >= memTotal {
+		limits.MemoryLimit = 0
+	}
+	if cores > 0 && limits.CPULimit >= float64(cores) {
+		limits.CPULimit = 0
+	}
+
+	if limits.MemoryLimit > 0 {
+		limits.MemoryPercent = float64(limits.MemoryUsage) / float64(limits.MemoryLimit) * 100
+	}
+	if limits.CPULimit > 0 {
+		limits.CPUPercent = limits.CPUUsage / limits.CPULimit * 100
+	}
+
+	if !limits.Limited() && limits.OOMKills == 0 {
+		return nil
+	}
+	return limits
+}
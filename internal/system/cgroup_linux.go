@@ -0,0 +1,250 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupMount is one cgroup hierarchy from /proc/self/mountinfo. Root is
+// the cgroup path mounted at Point; inside a container it is usually the
+// container's own cgroup rather than "/".
+type cgroupMount struct {
+	root        string
+	point       string
+	v2          bool
+	controllers map[string]bool
+}
+
+// cgroupV1Unlimited is above any real limit; v1 reports "unlimited" as the
+// largest page-aligned int64.
+const cgroupV1Unlimited = 1 << 62
+
+// readCgroupLimits reads the limits of the cgroup csys runs in, taking the
+// tightest one along the path to the root, and its cumulative CPU usage in
+// nanoseconds. It works on v2, v1 and hybrid hierarchies; ok is false when
+// no cgroup controllers are mounted.
+func readCgroupLimits() (*CgroupLimits, uint64, bool) {
+	mounts := parseCgroupMounts(readCgroupFile("/proc/self/mountinfo"))
+	paths := parseSelfCgroups(readCgroupFile("/proc/self/cgroup"))
+	return cgroupLimits(mounts, paths)
+}
+
+// cgroupLimits reads the limits from the cgroup at paths in mounts.
+func cgroupLimits(mounts []cgroupMount, paths map[string]string) (*CgroupLimits, uint64, bool) {
+	if len(mounts) == 0 || len(paths) == 0 {
+		return nil, 0, false
+	}
+
+	limits := &CgroupLimits{Version: 1, Path: paths["memory"]}
+	if limits.Path == "" {
+		limits.Path = paths[""]
+	}
+	found := false
+	var usage uint64
+
+	if dir, top, ok := cgroupDir(mounts, paths, "memory", "memory.max"); ok {
+		found = true
+		limits.Version, limits.Path = 2, paths[""]
+		limits.MemoryLimit = minCgroupValue(dir, top, "memory.max")
+		limits.MemoryUsage = cgroupWorkingSet(dir, "memory.current", "inactive_file")
+		limits.OOMKills, _ = readCgroupKey(filepath.Join(dir, "memory.events"), "oom_kill")
+	} else if dir, top, ok := cgroupDir(mounts, paths, "memory", "memory.limit_in_bytes"); ok {
+		found = true
+		limits.Path = paths["memory"]
+		limits.MemoryLimit = minCgroupValue(dir, top, "memory.limit_in_bytes")
+		limits.MemoryUsage = cgroupWorkingSet(dir, "memory.usage_in_bytes", "total_inactive_file")
+		limits.OOMKills, _ = readCgroupKey(filepath.Join(dir, "memory.oom_control"), "oom_kill")
+	}
+
+	if dir, top, ok := cgroupDir(mounts, paths, "cpu", "cpu.max"); ok {
+		found = true
+		limits.CPULimit = minCPUQuota(dir, top, func(d string) (float64, float64, bool) {
+			fields := strings.Fields(readCgroupFile(filepath.Join(d, "cpu.max")))
+			if len(fields) != 2 {
+				return 0, 0, false
+			}
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			return quota, period, err1 == nil && err2 == nil
+		})
+	} else if dir, top, ok := cgroupDir(mounts, paths, "cpu", "cpu.cfs_quota_us"); ok {
+		found = true
+		limits.CPULimit = minCPUQuota(dir, top, func(d string) (float64, float64, bool) {
+			quota, err1 := strconv.ParseFloat(readCgroupFile(filepath.Join(d, "cpu.cfs_quota_us")), 64)
+			period, err2 := strconv.ParseFloat(readCgroupFile(filepath.Join(d, "cpu.cfs_period_us")), 64)
+			return quota, period, err1 == nil && err2 == nil
+		})
+	}
+
+	// cpu.stat has usage_usec on v2 even where the cpu controller is not
+	// enabled; v1 counts nanoseconds in cpuacct.usage.
+	if dir, _, ok := cgroupDir(mounts, paths, "cpu", "cpu.stat"); ok {
+		if usec, ok := readCgroupKey(filepath.Join(dir, "cpu.stat"), "usage_usec"); ok {
+			usage = usec * 1000
+		}
+	}
+	if usage == 0 {
+		if dir, _, ok := cgroupDir(mounts, paths, "cpuacct", "cpuacct.usage"); ok {
+			usage, _ = readCgroupUint(filepath.Join(dir, "cpuacct.usage"))
+		}
+	}
+
+	if dir, top, ok := cgroupDir(mounts, paths, "pids", "pids.max"); ok {
+		found = true
+		limits.PIDsLimit = minCgroupValue(dir, top, "pids.max")
+		limits.PIDsCurrent, _ = readCgroupUint(filepath.Join(dir, "pids.current"))
+	}
+
+	return limits, usage, found
+}
+
+// cgroupDir returns the directory of csys's cgroup in the hierarchy that
+// has file (which tells v2 and v1 controllers apart) and that hierarchy's
+// mount point, where walks up the tree stop.
+func cgroupDir(mounts []cgroupMount, paths map[string]string, controller, file string) (string, string, bool) {
+	for _, m := range mounts {
+		path, ok := paths[""]
+		if !m.v2 {
+			if !m.controllers[controller] {
+				continue
+			}
+			path, ok = paths[controller]
+		}
+		if !ok {
+			continue
+		}
+
+		dir := m.point
+		if rel, err := filepath.Rel(m.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = filepath.Join(m.point, rel)
+		}
+		// Without a cgroup namespace, the host path may not exist in the
+		// container's view; its mount point is then its own cgroup.
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			dir = m.point
+		}
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return dir, m.point, true
+		}
+	}
+	return "", "", false
+}
+
+// minCgroupValue is the smallest limit in file from dir up to top; a parent
+// slice can be tighter than the cgroup itself. 0 means unlimited.
+func minCgroupValue(dir, top, file string) uint64 {
+	var limit uint64
+	for d := dir; ; d = filepath.Dir(d) {
+		if v, ok := readCgroupUint(filepath.Join(d, file)); ok && v > 0 && v < cgroupV1Unlimited {
+			if limit == 0 || v < limit {
+				limit = v
+			}
+		}
+		if d == top || d == filepath.Dir(d) {
+			return limit
+		}
+	}
+}
+
+func minCPUQuota(dir, top string, read func(string) (float64, float64, bool)) float64 {
+	var limit float64
+	for d := dir; ; d = filepath.Dir(d) {
+		// "max" (v2) and -1 (v1) mean no quota; neither parses as positive.
+		if quota, period, ok := read(d); ok && quota > 0 && period > 0 {
+			if cores := quota / period; limit == 0 || cores < limit {
+				limit = cores
+			}
+		}
+		if d == top || d == filepath.Dir(d) {
+			return limit
+		}
+	}
+}
+
+func cgroupWorkingSet(dir, usageFile, inactiveKey string) uint64 {
+	usage, _ := readCgroupUint(filepath.Join(dir, usageFile))
+	inactive, _ := readCgroupKey(filepath.Join(dir, "memory.stat"), inactiveKey)
+	if inactive > usage {
+		return 0
+	}
+	return usage - inactive
+}
+
+func readCgroupFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readCgroupUint reads a single-value file; "max" reads as not ok.
+func readCgroupUint(path string) (uint64, bool) {
+	v, err := strconv.ParseUint(readCgroupFile(path), 10, 64)
+	return v, err == nil
+}
+
+// readCgroupKey reads one "key value" line of a flat-keyed file such as
+// memory.stat or memory.events.
+func readCgroupKey(path, key string) (uint64, bool) {
+	for _, line := range strings.Split(readCgroupFile(path), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			v, err := strconv.ParseUint(fields[1], 10, 64)
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
+
+// parseCgroupMounts picks the cgroup hierarchies out of a mountinfo file.
+func parseCgroupMounts(mountinfo string) []cgroupMount {
+	var mounts []cgroupMount
+	for _, line := range strings.Split(mountinfo, "\n") {
+		// ID parent major:minor root point options [optional...] - fstype source superoptions
+		pre, post, ok := strings.Cut(line, " - ")
+		if !ok {
+			continue
+		}
+		fields, tail := strings.Fields(pre), strings.Fields(post)
+		if len(fields) < 5 || len(tail) < 3 {
+			continue
+		}
+
+		m := cgroupMount{root: fields[3], point: fields[4], controllers: make(map[string]bool)}
+		switch tail[0] {
+		case "cgroup2":
+			m.v2 = true
+		case "cgroup":
+			for _, opt := range strings.Split(tail[2], ",") {
+				m.controllers[opt] = true
+			}
+		default:
+			continue
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+// parseSelfCgroups maps each v1 controller in a /proc/<pid>/cgroup file to
+// the cgroup path in its hierarchy; the v2 path is under "".
+func parseSelfCgroups(data string) map[string]string {
+	paths := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			paths[""] = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+	return paths
+}
@@ -0,0 +1,173 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCgroupMounts(t *testing.T) {
+	mountinfo := `22 1 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:5 - proc proc rw
+25 1 0:23 / /sys/fs/cgroup ro,nosuid,nodev,noexec shared:4 - tmpfs tmpfs ro,mode=755
+26 25 0:24 / /sys/fs/cgroup/unified rw,nosuid,nodev,noexec,relatime shared:6 - cgroup2 cgroup2 rw,nsdelegate
+30 25 0:28 / /sys/fs/cgroup/memory rw,nosuid,nodev,noexec,relatime shared:11 - cgroup cgroup rw,memory
+31 25 0:29 /docker/abc /sys/fs/cgroup/cpu,cpuacct rw,nosuid,nodev,noexec,relatime - cgroup cgroup rw,cpu,cpuacct
+`
+	want := []cgroupMount{
+		{root: "/", point: "/sys/fs/cgroup/unified", v2: true, controllers: map[string]bool{}},
+		{root: "/", point: "/sys/fs/cgroup/memory", controllers: map[string]bool{"rw": true, "memory": true}},
+		{root: "/docker/abc", point: "/sys/fs/cgroup/cpu,cpuacct", controllers: map[string]bool{"rw": true, "cpu": true, "cpuacct": true}},
+	}
+
+	if got := parseCgroupMounts(mountinfo); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCgroupMounts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSelfCgroups(t *testing.T) {
+	data := "12:memory:/docker/abc\n11:cpu,cpuacct:/docker/abc\n1:name=systemd:/docker/abc\n0::/system.slice/docker-abc.scope"
+	want := map[string]string{
+		"memory":       "/docker/abc",
+		"cpu":          "/docker/abc",
+		"cpuacct":      "/docker/abc",
+		"name=systemd": "/docker/abc",
+		"":             "/system.slice/docker-abc.scope",
+	}
+
+	if got := parseSelfCgroups(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSelfCgroups() = %v, want %v", got, want)
+	}
+}
+
+func TestCgroupLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		mountinfo string
+		self      string
+		files     map[string]string
+		want      *CgroupLimits
+		wantUsage uint64
+		wantOK    bool
+	}{
+		{
+			name:      "v2 nested limits",
+			mountinfo: "35 24 0:30 / $ROOT/v2 rw,nosuid,nodev,noexec,relatime - cgroup2 cgroup2 rw",
+			self:      "0::/app.slice/web.service",
+			files: map[string]string{
+				// The slice's memory limit is tighter than the service's own.
+				"v2/app.slice/memory.max":                 "536870912",
+				"v2/app.slice/cpu.max":                    "max 100000",
+				"v2/app.slice/web.service/memory.max":     "1073741824",
+				"v2/app.slice/web.service/memory.current": "419430400",
+				"v2/app.slice/web.service/memory.stat":    "anon 314572800\ninactive_file 104857600\nactive_file 0",
+				"v2/app.slice/web.service/memory.events":  "low 0\nhigh 0\nmax 5\noom 2\noom_kill 2",
+				"v2/app.slice/web.service/cpu.max":        "150000 100000",
+				"v2/app.slice/web.service/cpu.stat":       "usage_usec 2500000\nuser_usec 2000000\nsystem_usec 500000",
+				"v2/app.slice/web.service/pids.max":       "100",
+				"v2/app.slice/web.service/pids.current":   "12",
+			},
+			want: &CgroupLimits{
+				Version:     2,
+				Path:        "/app.slice/web.service",
+				MemoryLimit: 536870912,
+				MemoryUsage: 314572800,
+				OOMKills:    2,
+				CPULimit:    1.5,
+				PIDsLimit:   100,
+				PIDsCurrent: 12,
+			},
+			wantUsage: 2500000000,
+			wantOK:    true,
+		},
+		{
+			name: "v1 without a cgroup namespace",
+			mountinfo: strings.Join([]string{
+				"30 25 0:28 / $ROOT/v1/memory rw - cgroup cgroup rw,memory",
+				"31 25 0:29 / $ROOT/v1/cpu,cpuacct rw - cgroup cgroup rw,cpu,cpuacct",
+				// The pids hierarchy is mounted at the container's own cgroup,
+				// so its path is not under the mount point.
+				"32 25 0:30 /docker/abc $ROOT/v1/pids rw - cgroup cgroup rw,pids",
+			}, "\n"),
+			self: "12:memory:/docker/abc\n11:cpu,cpuacct:/docker/abc\n10:pids:/docker/abc",
+			files: map[string]string{
+				"v1/memory/docker/memory.limit_in_bytes":      "9223372036854771712",
+				"v1/memory/docker/abc/memory.limit_in_bytes":  "268435456",
+				"v1/memory/docker/abc/memory.usage_in_bytes":  "200000000",
+				"v1/memory/docker/abc/memory.stat":            "cache 60000000\ntotal_inactive_file 50000000",
+				"v1/memory/docker/abc/memory.oom_control":     "oom_kill_disable 0\nunder_oom 0\noom_kill 1",
+				"v1/cpu,cpuacct/docker/abc/cpu.cfs_quota_us":  "50000",
+				"v1/cpu,cpuacct/docker/abc/cpu.cfs_period_us": "100000",
+				"v1/cpu,cpuacct/docker/abc/cpu.stat":          "nr_periods 10\nnr_throttled 2\nthrottled_time 1000",
+				"v1/cpu,cpuacct/docker/abc/cpuacct.usage":     "7000000000",
+				"v1/pids/pids.max":                            "64",
+				"v1/pids/pids.current":                        "3",
+			},
+			want: &CgroupLimits{
+				Version:     1,
+				Path:        "/docker/abc",
+				MemoryLimit: 268435456,
+				MemoryUsage: 150000000,
+				OOMKills:    1,
+				CPULimit:    0.5,
+				PIDsLimit:   64,
+				PIDsCurrent: 3,
+			},
+			wantUsage: 7000000000,
+			wantOK:    true,
+		},
+		{
+			name:      "v2 without limits",
+			mountinfo: "35 24 0:30 / $ROOT/v2 rw - cgroup2 cgroup2 rw",
+			self:      "0::/user.slice/session-1.scope",
+			files: map[string]string{
+				"v2/user.slice/session-1.scope/memory.max":     "max",
+				"v2/user.slice/session-1.scope/memory.current": "1000",
+				"v2/user.slice/session-1.scope/memory.events":  "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0",
+				"v2/user.slice/session-1.scope/cpu.max":        "max 100000",
+				"v2/user.slice/session-1.scope/cpu.stat":       "usage_usec 10",
+				"v2/user.slice/session-1.scope/pids.max":       "max",
+				"v2/user.slice/session-1.scope/pids.current":   "4",
+			},
+			want: &CgroupLimits{
+				Version:     2,
+				Path:        "/user.slice/session-1.scope",
+				MemoryUsage: 1000,
+				PIDsCurrent: 4,
+			},
+			wantUsage: 10000,
+			wantOK:    true,
+		},
+		{
+			name:      "no cgroup mounts",
+			mountinfo: "22 1 0:21 / /proc rw - proc proc rw",
+			self:      "0::/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				writeFixture(t, root, name, content)
+			}
+
+			mounts := parseCgroupMounts(strings.ReplaceAll(tt.mountinfo, "$ROOT", root))
+			got, usage, ok := cgroupLimits(mounts, parseSelfCgroups(tt.self))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("limits = %+v, want %+v", *got, *tt.want)
+			}
+			if usage != tt.wantUsage {
+				t.Errorf("usage = %d, want %d", usage, tt.wantUsage)
+			}
+			if got.Limited() != (tt.want.MemoryLimit > 0 || tt.want.CPULimit > 0 || tt.want.PIDsLimit > 0) {
+				t.Errorf("Limited() = %v", got.Limited())
+			}
+		})
+	}
+}
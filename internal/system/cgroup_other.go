@@ -0,0 +1,8 @@
+//go:build !linux
+
+package system
+
+// readCgroupLimits reports no cgroup; only Linux has them.
+func readCgroupLimits() (*CgroupLimits, uint64, bool) {
+	return nil, 0, false
+}
@@ -0,0 +1,154 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Thresholds are the usage percentages at which a metric turns yellow
+// (Warning) and red (Critical).
+type Thresholds struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+}
+
+var DefaultThresholds = Thresholds{Warning: 70, Critical: 90}
+
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+type CheckResult struct {
+	Check   string      `json:"check"`
+	Target  string      `json:"target"`
+	Status  CheckStatus `json:"status"`
+	Value   float64     `json:"value"`
+	Limit   float64     `json:"limit"`
+	Message string      `json:"message"`
+}
+
+type CheckReport struct {
+	Passed  bool          `json:"passed"`
+	Results []CheckResult `json:"results"`
+}
+
+// CheckRules describes what `csys check` evaluates. Percent limits of zero
+// fall back to Thresholds.Critical.
+type CheckRules struct {
+	Thresholds Thresholds
+	DiskMax    float64
+	MemMax     float64
+	CPUMax     float64
+	// AllDisks checks every partition instead of only the root volume.
+	AllDisks bool
+	// CPUWindow is how long CPU usage is measured for.
+	CPUWindow      time.Duration
+	PortsFree      []int
+	PortsListening []int
+	// PortProtocol is the protocol ("tcp" or "udp") the port checks look
+	// at; a UDP socket does not make a TCP port busy. TCP when empty.
+	PortProtocol string
+}
+
+func RunChecks(rules CheckRules) (*CheckReport, error) {
+	var results []CheckResult
+
+	var diskInfo *DiskInfo
+	var err error
+	if rules.AllDisks {
+		diskInfo, err = GetFullDiskInfo()
+	} else {
+		diskInfo, err = GetDiskInfo()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk info: %w", err)
+	}
+	for _, p := range diskInfo.Partitions {
+		results = append(results, checkPercent("disk", p.Mountpoint, p.Percent, rules.DiskMax, rules.Thresholds))
+	}
+
+	memInfo, err := GetMemoryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory info: %w", err)
+	}
+	results = append(results, checkPercent("memory", "used", memInfo.UsedPercent, rules.MemMax, rules.Thresholds))
+
+	cpuInfo, err := SampleCPU(rules.CPUWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CPU info: %w", err)
+	}
+	results = append(results, checkPercent("cpu", "total", cpuInfo.Percent, rules.CPUMax, rules.Thresholds))
+
+	if len(rules.PortsFree) > 0 || len(rules.PortsListening) > 0 {
+		ports, err := GetListeningPorts()
+		if err != nil {
+			return nil, err
+		}
+
+		protocol := rules.PortProtocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		listening := make(map[int]PortInfo, len(ports))
+		for _, p := range ports {
+			if p.Protocol == protocol {
+				listening[p.Port] = p
+			}
+		}
+
+		for _, port := range rules.PortsFree {
+			r := CheckResult{Check: "port_free", Target: strconv.Itoa(port), Value: float64(port), Status: CheckOK, Message: "free"}
+			if p, ok := listening[port]; ok {
+				r.Status = CheckFail
+				r.Message = fmt.Sprintf("in use by %s [PID: %d]", p.ProcessName, p.PID)
+			}
+			results = append(results, r)
+		}
+
+		for _, port := range rules.PortsListening {
+			r := CheckResult{Check: "port_listening", Target: strconv.Itoa(port), Value: float64(port), Status: CheckFail, Message: "nothing listening"}
+			if p, ok := listening[port]; ok {
+				r.Status = CheckOK
+				r.Message = fmt.Sprintf("%s [PID: %d]", p.ProcessName, p.PID)
+			}
+			results = append(results, r)
+		}
+	}
+
+	report := &CheckReport{Passed: true, Results: results}
+	for _, r := range results {
+		if r.Status == CheckFail {
+			report.Passed = false
+		}
+	}
+
+	return report, nil
+}
+
+func checkPercent(check, target string, value, limit float64, t Thresholds) CheckResult {
+	if limit <= 0 {
+		limit = t.Critical
+	}
+
+	r := CheckResult{
+		Check:   check,
+		Target:  target,
+		Value:   value,
+		Limit:   limit,
+		Status:  CheckOK,
+		Message: fmt.Sprintf("%.0f%% (max %.0f%%)", value, limit),
+	}
+
+	if value > limit {
+		r.Status = CheckFail
+	} else if value >= t.Warning {
+		r.Status = CheckWarn
+	}
+
+	return r
+}
@@ -0,0 +1,210 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+type ConnectionInfo struct {
+	Protocol    string `json:"protocol"`
+	Family      string `json:"family"`
+	LocalAddr   string `json:"local_address"`
+	LocalPort   int    `json:"local_port"`
+	RemoteAddr  string `json:"remote_address"`
+	RemotePort  int    `json:"remote_port"`
+	State       string `json:"state"`
+	PID         int32  `json:"pid"`
+	ProcessName string `json:"process_name"`
+}
+
+type ConnectionsReport struct {
+	Connections []ConnectionInfo `json:"connections"`
+	StateCounts map[string]int   `json:"state_counts"`
+}
+
+type ConnectionFilter struct {
+	// States to include (e.g. "ESTABLISHED", "CLOSE_WAIT"). Empty means every
+	// state except LISTEN; "ALL" includes listeners too.
+	States []string
+	// Port matches either the local or the remote port; 0 matches any.
+	Port int
+}
+
+// NormalizeState turns user input such as "close-wait" or "timewait" into the
+// state names reported by the kernel ("CLOSE_WAIT", "TIME_WAIT").
+func NormalizeState(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "-", "_")
+
+	switch s {
+	case "TIMEWAIT":
+		return "TIME_WAIT"
+	case "CLOSEWAIT":
+		return "CLOSE_WAIT"
+	case "SYNSENT":
+		return "SYN_SENT"
+	case "SYNRECV", "SYN_RECEIVED":
+		return "SYN_RECV"
+	case "FIN_WAIT_1":
+		return "FIN_WAIT1"
+	case "FIN_WAIT_2":
+		return "FIN_WAIT2"
+	}
+	return s
+}
+
+func GetConnections(filter ConnectionFilter) (*ConnectionsReport, error) {
+	conns, err := net.Connections("inet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(filter.States))
+	for _, s := range filter.States {
+		wanted[NormalizeState(s)] = true
+	}
+
+	report := &ConnectionsReport{
+		Connections: make([]ConnectionInfo, 0),
+		StateCounts: make(map[string]int),
+	}
+	names := newProcessCache()
+
+	for _, conn := range conns {
+		// Unconnected UDP sockets have no state and no peer; they are
+		// listeners, not connections.
+		if conn.Status == "" || conn.Status == "NONE" {
+			continue
+		}
+
+		switch {
+		case wanted["ALL"]:
+		case len(wanted) > 0:
+			if !wanted[conn.Status] {
+				continue
+			}
+		case conn.Status == "LISTEN":
+			continue
+		}
+
+		if filter.Port != 0 && int(conn.Laddr.Port) != filter.Port && int(conn.Raddr.Port) != filter.Port {
+			continue
+		}
+
+		name, _ := names.lookup(conn.Pid)
+
+		report.Connections = append(report.Connections, ConnectionInfo{
+			Protocol:    socketProtocol(conn.Type),
+			Family:      socketFamily(conn.Family),
+			LocalAddr:   conn.Laddr.IP,
+			LocalPort:   int(conn.Laddr.Port),
+			RemoteAddr:  conn.Raddr.IP,
+			RemotePort:  int(conn.Raddr.Port),
+			State:       conn.Status,
+			PID:         conn.Pid,
+			ProcessName: name,
+		})
+		report.StateCounts[conn.Status]++
+	}
+
+	sort.Slice(report.Connections, func(i, j int) bool {
+		a, b := report.Connections[i], report.Connections[j]
+		if a.State != b.State {
+			return a.State < b.State
+		}
+		if a.LocalPort != b.LocalPort {
+			return a.LocalPort < b.LocalPort
+		}
+		return a.RemotePort < b.RemotePort
+	})
+
+	return report, nil
+}
+
+func socketProtocol(sockType uint32) string {
+	if sockType == syscall.SOCK_DGRAM {
+		return "udp"
+	}
+	return "tcp"
+}
+
+func socketFamily(family uint32) string {
+	if family == syscall.AF_INET6 {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// processCache avoids inspecting the same process once per socket it owns.
+type processCache struct {
+	entries    map[int32]processCacheEntry
+	containers *ContainerResolver
+}
+
+type processCacheEntry struct {
+	name   string
+	memory uint64
+	user   string
+	uid    int32
+}
+
+func newProcessCache() *processCache {
+	return &processCache{
+		entries:    make(map[int32]processCacheEntry),
+		containers: NewContainerResolver(),
+	}
+}
+
+// lookup returns the process name ("unknown" if it cannot be read) and RSS.
+func (c *processCache) lookup(pid int32) (string, uint64) {
+	e := c.get(pid)
+	return e.name, e.memory
+}
+
+// owner returns the user name and effective UID of the process, or "" and -1
+// when they cannot be read.
+func (c *processCache) owner(pid int32) (string, int32) {
+	e := c.get(pid)
+	return e.user, e.uid
+}
+
+// container returns the container the process runs in or proxies for.
+func (c *processCache) container(pid int32) *ContainerInfo {
+	if pid == 0 {
+		return nil
+	}
+	return c.containers.ForPID(pid)
+}
+
+func (c *processCache) get(pid int32) processCacheEntry {
+	if e, ok := c.entries[pid]; ok {
+		return e
+	}
+
+	e := processCacheEntry{name: "unknown", uid: -1}
+	if pid != 0 {
+		if p, err := process.NewProcess(pid); err == nil {
+			if name, err := p.Name(); err == nil {
+				e.name = name
+			}
+			if memInfo, err := p.MemoryInfo(); err == nil {
+				e.memory = memInfo.RSS
+			}
+			if user, err := p.Username(); err == nil {
+				e.user = user
+			}
+			// Uids is [real, effective, saved, filesystem].
+			if uids, err := p.Uids(); err == nil && len(uids) > 1 {
+				e.uid = uids[1]
+			}
+		}
+	}
+
+	c.entries[pid] = e
+	return e
+}
@@ -0,0 +1,365 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContainerInfo names the container a process runs in. Everything is read
+// from /proc and the runtimes' state on disk; no daemon is contacted.
+type ContainerInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Runtime string `json:"runtime"`
+	// Proxy is set when the process is a docker-proxy forwarding a
+	// published port to the container rather than running inside it.
+	Proxy bool `json:"proxy,omitempty"`
+}
+
+// ShortID is the 12-character ID docker ps shows.
+func (c ContainerInfo) ShortID() string {
+	if len(c.ID) > 12 {
+		return c.ID[:12]
+	}
+	return c.ID
+}
+
+// DisplayName is the container name, or its short ID when the runtime's
+// metadata could not be read.
+func (c ContainerInfo) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ShortID()
+}
+
+// ContainerResolver maps processes to containers. The roots default to the
+// standard locations and can be pointed at a copy of them.
+type ContainerResolver struct {
+	ProcRoot string
+	// DockerRoot holds containers/<id>/config.v2.json.
+	DockerRoot string
+	// ContainerdRoots hold <namespace>/<id>/config.json, the OCI spec with
+	// the CRI and nerdctl name annotations.
+	ContainerdRoots []string
+	// StorageRoots are podman and CRI-O stores, with
+	// overlay-containers/containers.json.
+	StorageRoots []string
+
+	pids     map[int32]*ContainerInfo
+	metadata map[string]*ContainerInfo
+	// dockerIPs maps container IP addresses to IDs, for docker-proxy.
+	dockerIPs map[string]string
+	storage   map[string]*ContainerInfo
+}
+
+func NewContainerResolver() *ContainerResolver {
+	r := &ContainerResolver{
+		ProcRoot:   "/proc",
+		DockerRoot: "/var/lib/docker",
+		ContainerdRoots: []string{
+			"/run/containerd/io.containerd.runtime.v2.task",
+		},
+		StorageRoots: []string{"/var/lib/containers/storage"},
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		r.StorageRoots = append(r.StorageRoots, filepath.Join(home, ".local/share/containers/storage"))
+	}
+	return r
+}
+
+// ForPID returns the container pid runs in, or the one it publishes a port
+// for if it is a docker-proxy. Nil for host processes.
+func (r *ContainerResolver) ForPID(pid int32) *ContainerInfo {
+	if r.pids == nil {
+		r.pids = make(map[int32]*ContainerInfo)
+	}
+	if c, ok := r.pids[pid]; ok {
+		return c
+	}
+
+	var c *ContainerInfo
+	if id, runtime := r.containerID(pid); id != "" {
+		c = r.lookup(id, runtime)
+	} else if id := r.proxiedContainer(pid); id != "" {
+		proxied := *r.lookup(id, "docker")
+		proxied.Proxy = true
+		c = &proxied
+	}
+
+	r.pids[pid] = c
+	return c
+}
+
+// containerID reads the container ID and runtime from /proc/<pid>/cgroup.
+func (r *ContainerResolver) containerID(pid int32) (string, string) {
+	data, err := os.ReadFile(filepath.Join(r.ProcRoot, fmt.Sprint(pid), "cgroup"))
+	if err != nil {
+		return "", ""
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if id, runtime := parseContainerCgroup(parts[2]); id != "" {
+			return id, runtime
+		}
+	}
+	return "", ""
+}
+
+// cgroupScopePrefixes are the systemd scope names runtimes create, e.g.
+// docker-<id>.scope. The conmon scopes hold podman's monitor, not the
+// container.
+var cgroupScopePrefixes = []struct {
+	prefix  string
+	runtime string
+}{
+	{"docker-", "docker"},
+	{"cri-containerd-", "containerd"},
+	{"crio-", "cri-o"},
+	{"libpod-", "podman"},
+}
+
+// parseContainerCgroup finds a container ID in a cgroup path, either as a
+// scope (/system.slice/docker-<id>.scope) or as a directory named by the
+// ID (/docker/<id>, /kubepods/burstable/pod.../<id>).
+func parseContainerCgroup(path string) (string, string) {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := strings.TrimSuffix(segments[i], ".scope")
+		if strings.Contains(seg, "conmon") {
+			continue
+		}
+		for _, s := range cgroupScopePrefixes {
+			if id := strings.TrimPrefix(seg, s.prefix); id != seg && isContainerID(id) {
+				return id, s.runtime
+			}
+		}
+		if isContainerID(seg) {
+			switch {
+			case strings.Contains(path, "kubepods"):
+				return seg, "kubernetes"
+			case strings.Contains(path, "libpod"):
+				return seg, "podman"
+			case i > 0 && segments[i-1] == "docker":
+				return seg, "docker"
+			}
+			return seg, "container"
+		}
+	}
+	return "", ""
+}
+
+func isContainerID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// proxiedContainer returns the docker container a docker-proxy forwards
+// to, matched by the -container-ip argument on its command line.
+func (r *ContainerResolver) proxiedContainer(pid int32) string {
+	data, err := os.ReadFile(filepath.Join(r.ProcRoot, fmt.Sprint(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	if len(args) == 0 || filepath.Base(args[0]) != "docker-proxy" {
+		return ""
+	}
+
+	for i, arg := range args {
+		if arg == "-container-ip" && i+1 < len(args) {
+			r.loadDockerIPs()
+			return r.dockerIPs[args[i+1]]
+		}
+	}
+	return ""
+}
+
+// lookup returns the container's metadata from whichever runtime has it,
+// or just the ID.
+func (r *ContainerResolver) lookup(id, runtime string) *ContainerInfo {
+	if r.metadata == nil {
+		r.metadata = make(map[string]*ContainerInfo)
+	}
+	if c, ok := r.metadata[id]; ok {
+		return c
+	}
+
+	c, _ := r.readDockerConfig(id)
+	if c == nil {
+		c = r.readContainerdSpec(id)
+	}
+	if c == nil {
+		// Podman and CRI-O share the store layout; the cgroup tells them apart.
+		if stored := r.readStorage(id); stored != nil {
+			copied := *stored
+			if runtime != "container" {
+				copied.Runtime = runtime
+			}
+			c = &copied
+		}
+	}
+	if c == nil {
+		c = &ContainerInfo{ID: id, Runtime: runtime}
+	}
+
+	r.metadata[id] = c
+	return c
+}
+
+// dockerConfig is the part of docker's config.v2.json used here.
+type dockerConfig struct {
+	ID     string `json:"ID"`
+	Name   string `json:"Name"`
+	Config struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress         string `json:"IPAddress"`
+			GlobalIPv6Address string `json:"GlobalIPv6Address"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+func (r *ContainerResolver) readDockerConfig(id string) (*ContainerInfo, *dockerConfig) {
+	data, err := os.ReadFile(filepath.Join(r.DockerRoot, "containers", id, "config.v2.json"))
+	if err != nil {
+		return nil, nil
+	}
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, nil
+	}
+	return &ContainerInfo{
+		ID:      id,
+		Name:    strings.TrimPrefix(cfg.Name, "/"),
+		Image:   cfg.Config.Image,
+		Runtime: "docker",
+	}, &cfg
+}
+
+func (r *ContainerResolver) loadDockerIPs() {
+	if r.dockerIPs != nil {
+		return
+	}
+	r.dockerIPs = make(map[string]string)
+
+	entries, err := os.ReadDir(filepath.Join(r.DockerRoot, "containers"))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		_, cfg := r.readDockerConfig(e.Name())
+		if cfg == nil {
+			continue
+		}
+		for _, n := range cfg.NetworkSettings.Networks {
+			for _, ip := range []string{n.IPAddress, n.GlobalIPv6Address} {
+				if ip != "" {
+					r.dockerIPs[ip] = e.Name()
+				}
+			}
+		}
+	}
+}
+
+// ociSpec is the part of a containerd task's config.json used here. The
+// annotations name the container for nerdctl and Kubernetes.
+type ociSpec struct {
+	Annotations map[string]string `json:"annotations"`
+}
+
+func (r *ContainerResolver) readContainerdSpec(id string) *ContainerInfo {
+	for _, root := range r.ContainerdRoots {
+		matches, _ := filepath.Glob(filepath.Join(root, "*", id, "config.json"))
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var spec ociSpec
+			if err := json.Unmarshal(data, &spec); err != nil {
+				continue
+			}
+
+			a := spec.Annotations
+			c := &ContainerInfo{ID: id, Runtime: "containerd", Image: a["io.kubernetes.cri.image-name"]}
+			switch {
+			case a["nerdctl/name"] != "":
+				c.Name = a["nerdctl/name"]
+			case a["io.kubernetes.cri.container-name"] != "":
+				c.Name = a["io.kubernetes.cri.sandbox-name"] + "/" + a["io.kubernetes.cri.container-name"]
+				c.Runtime = "kubernetes"
+			}
+			return c
+		}
+	}
+	return nil
+}
+
+// storageContainer is an entry of containers.json in a podman or CRI-O
+// store. Metadata is itself JSON-encoded.
+type storageContainer struct {
+	ID       string   `json:"id"`
+	Names    []string `json:"names"`
+	Metadata string   `json:"metadata"`
+}
+
+func (r *ContainerResolver) readStorage(id string) *ContainerInfo {
+	if r.storage == nil {
+		r.storage = make(map[string]*ContainerInfo)
+		for _, root := range r.StorageRoots {
+			r.loadStorage(filepath.Join(root, "overlay-containers", "containers.json"))
+		}
+	}
+	return r.storage[id]
+}
+
+func (r *ContainerResolver) loadStorage(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var entries []storageContainer
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		c := &ContainerInfo{ID: e.ID, Runtime: "podman"}
+		if len(e.Names) > 0 {
+			c.Name = e.Names[0]
+		}
+		var meta struct {
+			ImageName string `json:"image-name"`
+		}
+		if json.Unmarshal([]byte(e.Metadata), &meta) == nil {
+			c.Image = meta.ImageName
+		}
+		r.storage[e.ID] = c
+	}
+}
+
+// AttachContainers sets Container on every process that runs in (or
+// proxies for) a container.
+func AttachContainers(procs []ProcessInfo) {
+	r := NewContainerResolver()
+	for i := range procs {
+		procs[i].Container = r.ForPID(procs[i].PID)
+	}
+}
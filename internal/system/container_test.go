@@ -0,0 +1,173 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	dockerID     = "3f4e2d1c0b9a8f7e6d5c4b3a29180716253f4e2d1c0b9a8f7e6d5c4b3a291807"
+	containerdID = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	kubeID       = "0011223344556677889900aabbccddeeff0011223344556677889900aabbccdd"
+	podmanID     = "c0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ff"
+	crioID       = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+)
+
+func TestParseContainerCgroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		wantID      string
+		wantRuntime string
+	}{
+		{"docker v1", "/docker/" + dockerID, dockerID, "docker"},
+		{"docker v2 scope", "/system.slice/docker-" + dockerID + ".scope", dockerID, "docker"},
+		{"containerd scope", "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1234.slice/cri-containerd-" + containerdID + ".scope", containerdID, "containerd"},
+		{"crio scope", "/kubepods.slice/kubepods-burstable.slice/crio-" + crioID + ".scope", crioID, "cri-o"},
+		{"podman scope", "/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + podmanID + ".scope", podmanID, "podman"},
+		{"podman conmon", "/machine.slice/libpod-conmon-" + podmanID + ".scope", "", ""},
+		{"kubepods cgroupfs", "/kubepods/burstable/pod0b1c2d3e/" + kubeID, kubeID, "kubernetes"},
+		{"libpod cgroupfs", "/libpod_parent/libpod-" + podmanID, podmanID, "podman"},
+		{"bare id", "/lxc/" + dockerID, dockerID, "container"},
+		{"host service", "/system.slice/sshd.service", "", ""},
+		{"root", "/", "", ""},
+		{"short hex", "/docker/3f4e2d1c0b9a", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, runtime := parseContainerCgroup(tt.path)
+			if id != tt.wantID || runtime != tt.wantRuntime {
+				t.Errorf("parseContainerCgroup(%q) = %q, %q; want %q, %q", tt.path, id, runtime, tt.wantID, tt.wantRuntime)
+			}
+		})
+	}
+}
+
+func TestContainerResolverForPID(t *testing.T) {
+	root := t.TempDir()
+	r := &ContainerResolver{
+		ProcRoot:        filepath.Join(root, "proc"),
+		DockerRoot:      filepath.Join(root, "docker"),
+		ContainerdRoots: []string{filepath.Join(root, "containerd")},
+		StorageRoots:    []string{filepath.Join(root, "storage")},
+	}
+
+	writeFixture(t, r.DockerRoot, "containers/"+dockerID+"/config.v2.json", `{
+		"ID": "`+dockerID+`",
+		"Name": "/web",
+		"Config": {"Image": "nginx:1.27"},
+		"NetworkSettings": {"Networks": {"bridge": {"IPAddress": "172.17.0.2"}}}
+	}`)
+	writeFixture(t, r.ContainerdRoots[0], "default/"+containerdID+"/config.json", `{
+		"annotations": {"nerdctl/name": "cache"}
+	}`)
+	writeFixture(t, r.ContainerdRoots[0], "k8s.io/"+kubeID+"/config.json", `{
+		"annotations": {
+			"io.kubernetes.cri.container-name": "api",
+			"io.kubernetes.cri.sandbox-name": "api-7d9f",
+			"io.kubernetes.cri.image-name": "registry.local/api:2"
+		}
+	}`)
+	writeFixture(t, r.StorageRoots[0], "overlay-containers/containers.json", `[
+		{"id": "`+podmanID+`", "names": ["db"], "metadata": "{\"image-name\":\"docker.io/library/postgres:16\"}"},
+		{"id": "`+crioID+`", "names": ["k8s_worker_pod"], "metadata": "{}"}
+	]`)
+
+	tests := []struct {
+		name    string
+		pid     int32
+		cgroup  string
+		cmdline []string
+		want    *ContainerInfo
+	}{
+		{
+			name:   "docker",
+			pid:    100,
+			cgroup: "0::/system.slice/docker-" + dockerID + ".scope",
+			want:   &ContainerInfo{ID: dockerID, Name: "web", Image: "nginx:1.27", Runtime: "docker"},
+		},
+		{
+			name:    "docker-proxy",
+			pid:     101,
+			cgroup:  "0::/system.slice/docker.service",
+			cmdline: []string{"/usr/bin/docker-proxy", "-proto", "tcp", "-host-ip", "0.0.0.0", "-host-port", "8080", "-container-ip", "172.17.0.2", "-container-port", "80"},
+			want:    &ContainerInfo{ID: dockerID, Name: "web", Image: "nginx:1.27", Runtime: "docker", Proxy: true},
+		},
+		{
+			name:    "docker-proxy to unknown ip",
+			pid:     102,
+			cgroup:  "0::/system.slice/docker.service",
+			cmdline: []string{"/usr/bin/docker-proxy", "-container-ip", "172.17.0.9"},
+		},
+		{
+			name:   "nerdctl",
+			pid:    200,
+			cgroup: "0::/default/" + containerdID,
+			want:   &ContainerInfo{ID: containerdID, Name: "cache", Runtime: "containerd"},
+		},
+		{
+			name:   "kubernetes",
+			pid:    201,
+			cgroup: "12:memory:/kubepods/burstable/pod0b1c2d3e/" + kubeID,
+			want:   &ContainerInfo{ID: kubeID, Name: "api-7d9f/api", Image: "registry.local/api:2", Runtime: "kubernetes"},
+		},
+		{
+			name:   "podman",
+			pid:    300,
+			cgroup: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + podmanID + ".scope/container",
+			want:   &ContainerInfo{ID: podmanID, Name: "db", Image: "docker.io/library/postgres:16", Runtime: "podman"},
+		},
+		{
+			name:   "cri-o",
+			pid:    301,
+			cgroup: "0::/kubepods.slice/kubepods-burstable.slice/crio-" + crioID + ".scope",
+			want:   &ContainerInfo{ID: crioID, Name: "k8s_worker_pod", Runtime: "cri-o"},
+		},
+		{
+			name:   "unknown container",
+			pid:    400,
+			cgroup: "0::/system.slice/docker-" + strings.Repeat("e", 64) + ".scope",
+			want:   &ContainerInfo{ID: strings.Repeat("e", 64), Runtime: "docker"},
+		},
+		{
+			name:   "host process",
+			pid:    500,
+			cgroup: "0::/user.slice/user-1000.slice/session-2.scope",
+		},
+	}
+
+	for _, tt := range tests {
+		dir := filepath.Join("proc", strconv.Itoa(int(tt.pid)))
+		writeFixture(t, root, filepath.Join(dir, "cgroup"), tt.cgroup+"\n")
+		writeFixture(t, root, filepath.Join(dir, "cmdline"), strings.Join(tt.cmdline, "\x00"))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.ForPID(tt.pid)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("ForPID(%d) = %+v, want nil", tt.pid, *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("ForPID(%d) = nil, want %+v", tt.pid, *tt.want)
+			case tt.want != nil && *got != *tt.want:
+				t.Errorf("ForPID(%d) = %+v, want %+v", tt.pid, *got, *tt.want)
+			}
+		})
+	}
+}
+
+func writeFixture(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,20 +1,119 @@
 package system
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
 )
 
-func GetCPUUsage() (float64, error) {
-	percentages, err := cpu.Percent(0, false)
+type CPUInfo struct {
+	Percent       float64     `json:"percent"`
+	PerCore       []float64   `json:"per_core_percent"`
+	LogicalCores  int         `json:"logical_cores"`
+	PhysicalCores int         `json:"physical_cores"`
+	Load          LoadAverage `json:"load_average"`
+	Times         CPUTimes    `json:"times_percent"`
+}
+
+// LoadAverage holds the 1/5/15-minute run queue averages, plus the same
+// values divided by the logical core count (1.0 = every core busy).
+type LoadAverage struct {
+	Load1         float64 `json:"load1"`
+	Load5         float64 `json:"load5"`
+	Load15        float64 `json:"load15"`
+	Load1PerCore  float64 `json:"load1_per_core"`
+	Load5PerCore  float64 `json:"load5_per_core"`
+	Load15PerCore float64 `json:"load15_per_core"`
+}
+
+// CPUTimes is the share of CPU time spent in each state, in percent.
+type CPUTimes struct {
+	User    float64 `json:"user"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle"`
+	Nice    float64 `json:"nice"`
+	IOWait  float64 `json:"iowait"`
+	IRQ     float64 `json:"irq"`
+	SoftIRQ float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+}
+
+// DefaultSampleWindow is how long one-shot readings wait between the two
+// counter samples they compare.
+const DefaultSampleWindow = 250 * time.Millisecond
+
+// CPUSampler computes CPU usage from the difference between consecutive
+// cpu.Times readings, so each Sample reports load since the previous call
+// rather than since boot.
+type CPUSampler struct {
+	prev          *cpu.TimesStat
+	prevPerCore   []cpu.TimesStat
+	physicalCores int
+}
+
+func NewCPUSampler() *CPUSampler {
+	return &CPUSampler{}
+}
+
+// Primed reports whether a previous reading exists to diff against.
+func (s *CPUSampler) Primed() bool {
+	return s.prev != nil
+}
+
+// Sample takes a new reading and returns usage since the previous one. The
+// first call has nothing to compare against and reports the average since boot.
+func (s *CPUSampler) Sample() (*CPUInfo, error) {
+	times, err := cpu.Times(false)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("no CPU times reported")
+	}
+
+	perCore, err := cpu.Times(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev cpu.TimesStat
+	if s.prev != nil {
+		prev = *s.prev
+	}
+
+	info := &CPUInfo{
+		Times:        timesPercent(times[0], prev),
+		PerCore:      make([]float64, len(perCore)),
+		LogicalCores: len(perCore),
+	}
+	info.Percent = busyPercent(info.Times)
+
+	for i, core := range perCore {
+		var prevCore cpu.TimesStat
+		if i < len(s.prevPerCore) {
+			prevCore = s.prevPerCore[i]
+		}
+		info.PerCore[i] = busyPercent(timesPercent(core, prevCore))
+	}
+
+	// Physical core count is not available everywhere (e.g. some VMs).
+	if s.physicalCores == 0 {
+		if physical, err := GetCPUCount(); err == nil {
+			s.physicalCores = physical
+		}
 	}
+	info.PhysicalCores = s.physicalCores
 
-	if len(percentages) > 0 {
-		return percentages[0], nil
+	if avg, err := load.Avg(); err == nil {
+		info.Load = newLoadAverage(avg, info.LogicalCores)
 	}
 
-	return 0, nil
+	s.prev = &times[0]
+	s.prevPerCore = perCore
+
+	return info, nil
 }
 
 func GetCPUCount() (int, error) {
@@ -24,3 +123,75 @@ func GetCPUCount() (int, error) {
 	}
 	return count, nil
 }
+
+// GetCPUInfo measures CPU usage over DefaultSampleWindow.
+func GetCPUInfo() (*CPUInfo, error) {
+	return SampleCPU(DefaultSampleWindow)
+}
+
+func SampleCPU(window time.Duration) (*CPUInfo, error) {
+	sampler := NewCPUSampler()
+	if _, err := sampler.Sample(); err != nil {
+		return nil, err
+	}
+	time.Sleep(window)
+	return sampler.Sample()
+}
+
+// busyPercent counts everything except idle and iowait as busy, matching
+// gopsutil's cpu.Percent.
+func busyPercent(t CPUTimes) float64 {
+	total := t.User + t.System + t.Idle + t.Nice + t.IOWait + t.IRQ + t.SoftIRQ + t.Steal
+	if total <= 0 {
+		return 0
+	}
+
+	busy := 100 - t.Idle - t.IOWait
+	if busy < 0 {
+		return 0
+	}
+	if busy > 100 {
+		return 100
+	}
+	return busy
+}
+
+// timesPercent converts the difference between two cumulative readings into
+// percentages. Passing a zero prev gives the split since boot.
+func timesPercent(cur, prev cpu.TimesStat) CPUTimes {
+	total := cur.Total() - prev.Total()
+	if total <= 0 {
+		return CPUTimes{}
+	}
+
+	pct := func(c, p float64) float64 {
+		return (c - p) / total * 100
+	}
+
+	return CPUTimes{
+		User:    pct(cur.User, prev.User),
+		System:  pct(cur.System, prev.System),
+		Idle:    pct(cur.Idle, prev.Idle),
+		Nice:    pct(cur.Nice, prev.Nice),
+		IOWait:  pct(cur.Iowait, prev.Iowait),
+		IRQ:     pct(cur.Irq, prev.Irq),
+		SoftIRQ: pct(cur.Softirq, prev.Softirq),
+		Steal:   pct(cur.Steal, prev.Steal),
+	}
+}
+
+func newLoadAverage(avg *load.AvgStat, cores int) LoadAverage {
+	l := LoadAverage{
+		Load1:  avg.Load1,
+		Load5:  avg.Load5,
+		Load15: avg.Load15,
+	}
+
+	if cores > 0 {
+		l.Load1PerCore = avg.Load1 / float64(cores)
+		l.Load5PerCore = avg.Load5 / float64(cores)
+		l.Load15PerCore = avg.Load15 / float64(cores)
+	}
+
+	return l
+}
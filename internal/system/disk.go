@@ -5,18 +5,19 @@ import (
 )
 
 type DiskInfo struct {
-	Partitions []DiskPartition
+	Partitions []DiskPartition `json:"partitions"`
 }
 
 type DiskPartition struct {
-	Mountpoint string
-	Device     string
-	Total      uint64
-	Used       uint64
-	Free       uint64
-	Percent    float64
-	Label      string
-	Category   string // "primary" or "system"
+	Mountpoint string  `json:"mountpoint"`
+	Device     string  `json:"device"`
+	Total      uint64  `json:"total_bytes"`
+	Used       uint64  `json:"used_bytes"`
+	Free       uint64  `json:"free_bytes"`
+	Percent    float64 `json:"used_percent"`
+	Label      string  `json:"label,omitempty"`
+	Category   string  `json:"category,omitempty"` // "primary" or "system"
+	IO         *DiskIO `json:"io,omitempty"`
 }
 
 func GetDiskInfo() (*DiskInfo, error) {
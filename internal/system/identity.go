@@ -0,0 +1,98 @@
+package system
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessIdentity pins a PID to one particular process. PIDs are recycled, so
+// a kill confirmed against one process must not land on whatever later gets
+// the same number; the start time and executable tell them apart.
+type ProcessIdentity struct {
+	PID       int32     `json:"pid"`
+	Name      string    `json:"process_name"`
+	StartTime time.Time `json:"start_time"`
+	Exe       string    `json:"exe,omitempty"`
+}
+
+// IdentifyProcess records what pid currently refers to.
+func IdentifyProcess(pid int32) (ProcessIdentity, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return ProcessIdentity{}, fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return ProcessIdentity{}, fmt.Errorf("failed to read start time of process %d: %w", pid, err)
+	}
+
+	id := ProcessIdentity{PID: pid, StartTime: time.UnixMilli(created)}
+	id.Name, _ = p.Name()
+	// Exe is unreadable for other users' processes without root; the start
+	// time alone still rules out reuse.
+	id.Exe, _ = p.Exe()
+	return id, nil
+}
+
+// verify checks that the PID still belongs to the identified process.
+func (id ProcessIdentity) verify() error {
+	if id.StartTime.IsZero() {
+		return fmt.Errorf("process %d could not be identified; not signalled", id.PID)
+	}
+
+	cur, err := IdentifyProcess(id.PID)
+	if err != nil {
+		return fmt.Errorf("process %d has exited", id.PID)
+	}
+	if !cur.StartTime.Equal(id.StartTime) || (id.Exe != "" && cur.Exe != "" && cur.Exe != id.Exe) {
+		return fmt.Errorf("PID %d now belongs to a different process (%s); not signalled", id.PID, cur.Name)
+	}
+	return nil
+}
+
+// processHandle signals and waits on one verified process.
+type processHandle interface {
+	signal(sig syscall.Signal) error
+	// waitExit reports whether the process exited within timeout.
+	waitExit(timeout time.Duration) bool
+	close()
+}
+
+// pidHandle addresses the process by PID and re-verifies its identity before
+// every signal. A PID can still be reused between the check and the signal,
+// but only within that narrow window.
+type pidHandle struct {
+	id ProcessIdentity
+}
+
+func newPIDHandle(id ProcessIdentity) (processHandle, error) {
+	if err := id.verify(); err != nil {
+		return nil, err
+	}
+	return &pidHandle{id: id}, nil
+}
+
+func (h *pidHandle) signal(sig syscall.Signal) error {
+	if err := h.id.verify(); err != nil {
+		return err
+	}
+	return SignalProcess(h.id.PID, sig)
+}
+
+func (h *pidHandle) waitExit(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !processAlive(h.id.PID) || h.id.verify() != nil {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(exitPollInterval)
+	}
+}
+
+func (h *pidHandle) close() {}
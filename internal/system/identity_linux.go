@@ -0,0 +1,74 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// pidfdHandle holds a pidfd, which keeps referring to the same process even
+// after it exits and its PID is reused, so signals cannot reach a stranger.
+type pidfdHandle struct {
+	id ProcessIdentity
+	fd int
+}
+
+// openProcess opens a pidfd and only then verifies the identity: once the fd
+// is open it cannot start pointing at another process. Kernels older than
+// 5.3 lack pidfds and fall back to PID checks.
+func openProcess(id ProcessIdentity) (processHandle, error) {
+	fd, err := unix.PidfdOpen(int(id.PID), 0)
+	if errors.Is(err, unix.ENOSYS) {
+		return newPIDHandle(id)
+	}
+	if errors.Is(err, unix.ESRCH) {
+		return nil, fmt.Errorf("process %d has exited", id.PID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open process %d: %w", id.PID, err)
+	}
+
+	if err := id.verify(); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return &pidfdHandle{id: id, fd: fd}, nil
+}
+
+func (h *pidfdHandle) signal(sig syscall.Signal) error {
+	if err := unix.PidfdSendSignal(h.fd, sig, nil, 0); err != nil {
+		if errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("process %d has exited", h.id.PID)
+		}
+		return fmt.Errorf("failed to signal process %d: %w", h.id.PID, err)
+	}
+	return nil
+}
+
+// waitExit polls the pidfd, which becomes readable when the process exits.
+func (h *pidfdHandle) waitExit(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fds := []unix.PollFd{{Fd: int32(h.fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining.Milliseconds()))
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			// Should not happen with a valid pidfd; fall back to polling.
+			return (&pidHandle{id: h.id}).waitExit(remaining)
+		}
+		return n > 0
+	}
+}
+
+func (h *pidfdHandle) close() {
+	unix.Close(h.fd)
+}
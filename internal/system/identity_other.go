@@ -0,0 +1,9 @@
+//go:build !linux
+
+package system
+
+// openProcess verifies the identity by start time and executable; without
+// pidfds the check is repeated before every signal.
+func openProcess(id ProcessIdentity) (processHandle, error) {
+	return newPIDHandle(id)
+}
@@ -5,11 +5,35 @@ import (
 )
 
 type MemoryInfo struct {
-	Total       uint64
-	Available   uint64
-	Used        uint64
-	Free        uint64
-	UsedPercent float64
+	Total       uint64  `json:"total_bytes"`
+	Available   uint64  `json:"available_bytes"`
+	Used        uint64  `json:"used_bytes"`
+	Free        uint64  `json:"free_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+
+	Buffers   uint64 `json:"buffers_bytes"`
+	Cached    uint64 `json:"cached_bytes"`
+	Shared    uint64 `json:"shared_bytes"`
+	Dirty     uint64 `json:"dirty_bytes"`
+	WriteBack uint64 `json:"writeback_bytes"`
+
+	// Only reported on Linux; zero elsewhere.
+	Slab           uint64 `json:"slab_bytes"`
+	HugePagesTotal uint64 `json:"hugepages_total"`
+	HugePagesFree  uint64 `json:"hugepages_free"`
+	HugePageSize   uint64 `json:"hugepage_size_bytes"`
+
+	Swap SwapInfo `json:"swap"`
+}
+
+type SwapInfo struct {
+	Total       uint64  `json:"total_bytes"`
+	Used        uint64  `json:"used_bytes"`
+	Free        uint64  `json:"free_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+	// Cumulative bytes paged in/out since boot, where the OS reports it.
+	SwappedIn  uint64 `json:"swapped_in_bytes"`
+	SwappedOut uint64 `json:"swapped_out_bytes"`
 }
 
 func GetMemoryInfo() (*MemoryInfo, error) {
@@ -18,11 +42,34 @@ func GetMemoryInfo() (*MemoryInfo, error) {
 		return nil, err
 	}
 
-	return &MemoryInfo{
-		Total:       memStats.Total,
-		Available:   memStats.Available,
-		Used:        memStats.Used,
-		Free:        memStats.Free,
-		UsedPercent: memStats.UsedPercent,
-	}, nil
+	info := &MemoryInfo{
+		Total:          memStats.Total,
+		Available:      memStats.Available,
+		Used:           memStats.Used,
+		Free:           memStats.Free,
+		UsedPercent:    memStats.UsedPercent,
+		Buffers:        memStats.Buffers,
+		Cached:         memStats.Cached,
+		Shared:         memStats.Shared,
+		Dirty:          memStats.Dirty,
+		WriteBack:      memStats.WriteBack,
+		Slab:           memStats.Slab,
+		HugePagesTotal: memStats.HugePagesTotal,
+		HugePagesFree:  memStats.HugePagesFree,
+		HugePageSize:   memStats.HugePageSize,
+	}
+
+	// Swap is optional: a machine without swap is still a valid reading.
+	if swap, err := mem.SwapMemory(); err == nil {
+		info.Swap = SwapInfo{
+			Total:       swap.Total,
+			Used:        swap.Used,
+			Free:        swap.Free,
+			UsedPercent: swap.UsedPercent,
+			SwappedIn:   swap.Sin,
+			SwappedOut:  swap.Sout,
+		}
+	}
+
+	return info, nil
 }
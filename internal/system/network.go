@@ -0,0 +1,167 @@
+package system
+
+import (
+	"fmt"
+	stdnet "net"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+type NetworkInfo struct {
+	Interfaces []NetInterface `json:"interfaces"`
+	// Totals across non-loopback interfaces.
+	RxBytesPerSec float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec float64 `json:"tx_bytes_per_sec"`
+}
+
+type NetInterface struct {
+	Name         string   `json:"name"`
+	MTU          int      `json:"mtu"`
+	HardwareAddr string   `json:"hardware_addr,omitempty"`
+	Addresses    []string `json:"addresses"`
+	Flags        []string `json:"flags"`
+	Up           bool     `json:"up"`
+	Running      bool     `json:"running"`
+	Loopback     bool     `json:"loopback"`
+
+	// Cumulative counters since the interface came up.
+	RxBytes   uint64 `json:"rx_bytes"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	TxPackets uint64 `json:"tx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	TxErrors  uint64 `json:"tx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxDropped uint64 `json:"tx_dropped"`
+
+	// Rates over the interval since the previous sample.
+	RxBytesPerSec   float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec   float64 `json:"tx_bytes_per_sec"`
+	RxPacketsPerSec float64 `json:"rx_packets_per_sec"`
+	TxPacketsPerSec float64 `json:"tx_packets_per_sec"`
+	ErrorsPerSec    float64 `json:"errors_per_sec"`
+	DropsPerSec     float64 `json:"drops_per_sec"`
+}
+
+// NetSampler reads per-interface counters and turns the difference between
+// consecutive readings into rates.
+type NetSampler struct {
+	prev map[string]net.IOCountersStat
+	last time.Time
+}
+
+func NewNetSampler() *NetSampler {
+	return &NetSampler{prev: make(map[string]net.IOCountersStat)}
+}
+
+// Sample returns every interface with rates since the previous Sample. The
+// first call has nothing to compare against and reports zero rates.
+func (s *NetSampler) Sample() (*NetworkInfo, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interfaces: %w", err)
+	}
+
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interface counters: %w", err)
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(s.last).Seconds()
+
+	byName := make(map[string]net.IOCountersStat, len(counters))
+	for _, c := range counters {
+		byName[c.Name] = c
+	}
+
+	info := &NetworkInfo{Interfaces: make([]NetInterface, 0, len(ifaces))}
+
+	for _, iface := range ifaces {
+		ni := NetInterface{
+			Name:         iface.Name,
+			MTU:          iface.MTU,
+			HardwareAddr: iface.HardwareAddr,
+			Addresses:    make([]string, 0, len(iface.Addrs)),
+			Flags:        iface.Flags,
+		}
+		if ni.Flags == nil {
+			ni.Flags = []string{}
+		}
+
+		for _, addr := range iface.Addrs {
+			ni.Addresses = append(ni.Addresses, addr.Addr)
+		}
+		// gopsutil does not report the running (carrier) flag; the
+		// standard library does.
+		if std, err := stdnet.InterfaceByName(iface.Name); err == nil && std.Flags&stdnet.FlagRunning != 0 {
+			ni.Flags = append(ni.Flags, "running")
+		}
+
+		for _, flag := range ni.Flags {
+			switch flag {
+			case "up":
+				ni.Up = true
+			case "running":
+				ni.Running = true
+			case "loopback":
+				ni.Loopback = true
+			}
+		}
+
+		if c, ok := byName[iface.Name]; ok {
+			ni.RxBytes = c.BytesRecv
+			ni.TxBytes = c.BytesSent
+			ni.RxPackets = c.PacketsRecv
+			ni.TxPackets = c.PacketsSent
+			ni.RxErrors = c.Errin
+			ni.TxErrors = c.Errout
+			ni.RxDropped = c.Dropin
+			ni.TxDropped = c.Dropout
+
+			if prev, ok := s.prev[iface.Name]; ok && !s.last.IsZero() && elapsed > 0 {
+				rate := func(cur, old uint64) float64 {
+					// Counters reset when an interface is re-created.
+					if cur < old {
+						return 0
+					}
+					return float64(cur-old) / elapsed
+				}
+				ni.RxBytesPerSec = rate(c.BytesRecv, prev.BytesRecv)
+				ni.TxBytesPerSec = rate(c.BytesSent, prev.BytesSent)
+				ni.RxPacketsPerSec = rate(c.PacketsRecv, prev.PacketsRecv)
+				ni.TxPacketsPerSec = rate(c.PacketsSent, prev.PacketsSent)
+				ni.ErrorsPerSec = rate(c.Errin+c.Errout, prev.Errin+prev.Errout)
+				ni.DropsPerSec = rate(c.Dropin+c.Dropout, prev.Dropin+prev.Dropout)
+			}
+		}
+
+		if !ni.Loopback {
+			info.RxBytesPerSec += ni.RxBytesPerSec
+			info.TxBytesPerSec += ni.TxBytesPerSec
+		}
+
+		info.Interfaces = append(info.Interfaces, ni)
+	}
+
+	sort.SliceStable(info.Interfaces, func(i, j int) bool {
+		return info.Interfaces[i].Name < info.Interfaces[j].Name
+	})
+
+	s.prev = byName
+	s.last = now
+
+	return info, nil
+}
+
+// GetNetworkInfo measures interface rates over window.
+func GetNetworkInfo(window time.Duration) (*NetworkInfo, error) {
+	sampler := NewNetSampler()
+	if _, err := sampler.Sample(); err != nil {
+		return nil, err
+	}
+	time.Sleep(window)
+	return sampler.Sample()
+}
@@ -0,0 +1,13 @@
+package system
+
+import "github.com/shirou/gopsutil/v3/process"
+
+// processNice returns the nice value (-20..19). gopsutil passes through the
+// raw getpriority syscall result, which Linux offsets to 1..40 (20 - nice).
+func processNice(p *process.Process) (int32, error) {
+	prio, err := p.Nice()
+	if err != nil {
+		return 0, err
+	}
+	return 20 - prio, nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package system
+
+import "github.com/shirou/gopsutil/v3/process"
+
+func processNice(p *process.Process) (int32, error) {
+	return p.Nice()
+}
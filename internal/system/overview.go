@@ -0,0 +1,148 @@
+package system
+
+import (
+	"fmt"
+	"time"
+)
+
+// Overview is everything shown by the default `csys` snapshot.
+type Overview struct {
+	Disk         *DiskInfo     `json:"disk"`
+	Memory       *MemoryInfo   `json:"memory"`
+	CPUPercent   float64       `json:"cpu_percent"`
+	CPU          *CPUInfo      `json:"cpu"`
+	Network      *NetworkInfo  `json:"network"`
+	TopProcesses []ProcessInfo `json:"top_processes"`
+	// GroupBy and TopGroups are set when the Collector aggregates processes.
+	GroupBy   ProcessGroupKey `json:"group_by,omitempty"`
+	TopGroups []ProcessGroup  `json:"top_groups,omitempty"`
+	// Cgroup is set when csys runs under memory, CPU or PID limits, e.g. in
+	// a container.
+	Cgroup *CgroupLimits `json:"cgroup,omitempty"`
+	// Pressure is Linux PSI; nil where the kernel does not provide it.
+	Pressure *PressureInfo `json:"pressure,omitempty"`
+}
+
+// Collector gathers Overviews and keeps the counter state needed to report
+// rates between calls. A one-shot snapshot and every tick of live mode use
+// the same Collector so the numbers mean the same thing in both.
+type Collector struct {
+	topN    int
+	warmup  time.Duration
+	groupBy ProcessGroupKey
+	cpu     *CPUSampler
+	procs   *ProcessSampler
+	net     *NetSampler
+	diskIO  *DiskIOSampler
+	cgroup  *CgroupSampler
+
+	processes []ProcessInfo
+}
+
+// NewCollector returns a Collector reporting the topN processes. The first
+// Collect call waits warmup between two readings so it reflects current
+// load; later calls report the interval since the previous Collect.
+func NewCollector(topN int, warmup time.Duration) *Collector {
+	return &Collector{
+		topN:   topN,
+		warmup: warmup,
+		cpu:    NewCPUSampler(),
+		procs:  NewProcessSampler(),
+		net:    NewNetSampler(),
+		diskIO: NewDiskIOSampler(),
+		cgroup: NewCgroupSampler(),
+	}
+}
+
+// SetGroupBy makes Collect also report the topN process groups by memory,
+// e.g. every chrome process summed into one entry. GroupByNone turns it off.
+func (c *Collector) SetGroupBy(key ProcessGroupKey) {
+	c.groupBy = key
+}
+
+func (c *Collector) prime() error {
+	if _, err := c.cpu.Sample(); err != nil {
+		return fmt.Errorf("failed to get CPU info: %w", err)
+	}
+	if _, err := c.procs.Sample(); err != nil {
+		return fmt.Errorf("failed to get process info: %w", err)
+	}
+	if _, err := c.net.Sample(); err != nil {
+		return fmt.Errorf("failed to get network info: %w", err)
+	}
+	// Disk I/O counters are missing in some containers; capacity still works.
+	c.diskIO.Sample()
+	c.cgroup.Sample(0, 0)
+	time.Sleep(c.warmup)
+	return nil
+}
+
+func (c *Collector) Collect() (*Overview, error) {
+	if !c.cpu.Primed() {
+		if err := c.prime(); err != nil {
+			return nil, err
+		}
+	}
+
+	diskInfo, err := GetDiskInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk info: %w", err)
+	}
+
+	if io, err := c.diskIO.Sample(); err == nil {
+		diskInfo.AttachIO(io)
+	}
+
+	memInfo, err := GetMemoryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory info: %w", err)
+	}
+
+	cpuInfo, err := c.cpu.Sample()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CPU info: %w", err)
+	}
+
+	netInfo, err := c.net.Sample()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network info: %w", err)
+	}
+
+	procs, err := c.procs.Sample()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process info: %w", err)
+	}
+	c.processes = procs
+
+	overview := &Overview{
+		Disk:         diskInfo,
+		Memory:       memInfo,
+		Cgroup:       c.cgroup.Sample(memInfo.Total, cpuInfo.LogicalCores),
+		CPUPercent:   cpuInfo.Percent,
+		CPU:          cpuInfo,
+		Network:      netInfo,
+		TopProcesses: topProcesses(procs, SortByRSS, c.topN),
+	}
+
+	// PSI needs Linux 4.20+ and can be disabled at boot; the rest still works.
+	if pressure, err := GetPressure(); err == nil {
+		overview.Pressure = pressure
+	}
+
+	if c.groupBy != GroupByNone {
+		groups := GroupProcesses(procs, c.groupBy)
+		if len(groups) > c.topN {
+			groups = groups[:c.topN]
+		}
+		overview.GroupBy = c.groupBy
+		overview.TopGroups = groups
+	}
+
+	return overview, nil
+}
+
+// Processes returns every process seen by the last Collect, with CPU%
+// measured over the same interval as the overview.
+func (c *Collector) Processes() []ProcessInfo {
+	return c.processes
+}
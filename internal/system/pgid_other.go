@@ -0,0 +1,11 @@
+//go:build !unix
+
+package system
+
+import "fmt"
+
+// processGroupID fails outside unix, which has no process groups; --group
+// then finds nothing beyond the port owner.
+func processGroupID(pid int32) (int, error) {
+	return 0, fmt.Errorf("process groups are not supported on this platform")
+}
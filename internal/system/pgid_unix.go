@@ -0,0 +1,10 @@
+//go:build unix
+
+package system
+
+import "syscall"
+
+// processGroupID returns the process group pid belongs to.
+func processGroupID(pid int32) (int, error) {
+	return syscall.Getpgid(int(pid))
+}
@@ -0,0 +1,133 @@
+package system
+
+import (
+	"fmt"
+	stdnet "net"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of port numbers.
+type PortRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// DefaultFreePortRange covers the registered ports: no root needed to bind
+// them, and they stay clear of the kernel's ephemeral range used for
+// outgoing connections.
+var DefaultFreePortRange = PortRange{Start: 1024, End: 49151}
+
+// ParsePortRange accepts "3000-3999" or a single port such as "8080".
+func ParsePortRange(s string) (PortRange, error) {
+	start, end, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		end = start
+	}
+
+	var r PortRange
+	var err error
+	if r.Start, err = parseRangePort(start); err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range %q: %w", s, err)
+	}
+	if r.End, err = parseRangePort(end); err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range %q: %w", s, err)
+	}
+	if r.Start > r.End {
+		return PortRange{}, fmt.Errorf("invalid port range %q: start is above end", s)
+	}
+	return r, nil
+}
+
+func parseRangePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a number", s)
+	}
+	if port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("port %d out of range (must be 1-65535)", port)
+	}
+	return port, nil
+}
+
+func (r PortRange) Contains(port int) bool {
+	return port >= r.Start && port <= r.End
+}
+
+func (r PortRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// FilterPorts keeps the listening ports inside r.
+func FilterPorts(ports []PortInfo, r PortRange) []PortInfo {
+	filtered := make([]PortInfo, 0, len(ports))
+	for _, p := range ports {
+		if r.Contains(p.Port) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+type FreePorts struct {
+	Range    PortRange `json:"range"`
+	Protocol string    `json:"protocol"`
+	Ports    []int     `json:"ports"`
+}
+
+// FindFreePorts returns up to count ports in r that nothing is listening on
+// for protocol ("tcp" or "udp"). Each candidate is also bound briefly on
+// every interface, which catches sockets hidden from the socket table, e.g.
+// ones owned by other users or network namespaces sharing the host.
+func FindFreePorts(r PortRange, count int, protocol string) (*FreePorts, error) {
+	if protocol != "tcp" && protocol != "udp" {
+		return nil, fmt.Errorf("invalid protocol %q (must be tcp or udp)", protocol)
+	}
+
+	ports, err := GetListeningPorts()
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[int]bool)
+	for _, p := range ports {
+		if p.Protocol == protocol {
+			taken[p.Port] = true
+		}
+	}
+
+	// The range caps the result however many ports were asked for.
+	if size := r.End - r.Start + 1; count > size {
+		count = size
+	}
+
+	result := &FreePorts{Range: r, Protocol: protocol, Ports: make([]int, 0, count)}
+	for port := r.Start; port <= r.End && len(result.Ports) < count; port++ {
+		if taken[port] || !canBind(protocol, port) {
+			continue
+		}
+		result.Ports = append(result.Ports, port)
+	}
+	return result, nil
+}
+
+func canBind(protocol string, port int) bool {
+	addr := ":" + strconv.Itoa(port)
+	if protocol == "udp" {
+		conn, err := stdnet.ListenPacket("udp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+
+	ln, err := stdnet.Listen("tcp", addr)
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
@@ -2,124 +2,261 @@ package system
 
 import (
 	"fmt"
+	stdnet "net"
 	"os"
-	"syscall"
-	"time"
+	"sort"
+	"strings"
 
 	"github.com/shirou/gopsutil/v3/net"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 type PortInfo struct {
-	Port        int
-	Protocol    string
-	State       string
-	ProcessName string
-	PID         int32
-	Memory      uint64
+	Port        int    `json:"port"`
+	Protocol    string `json:"protocol"`
+	Family      string `json:"family"`
+	Address     string `json:"address"`
+	State       string `json:"state"`
+	ProcessName string `json:"process_name"`
+	PID         int32  `json:"pid"`
+	Memory      uint64 `json:"memory_bytes"`
+	User        string `json:"user"`
+	// UID is the owning process's effective user ID, -1 when unknown.
+	UID int32 `json:"uid"`
+	// Container is the container the owner runs in or, for docker-proxy,
+	// publishes the port for.
+	Container *ContainerInfo `json:"container,omitempty"`
 }
 
+// IsLoopback reports whether the socket only accepts local connections.
+func (p PortInfo) IsLoopback() bool {
+	ip := stdnet.ParseIP(p.Address)
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsWildcard reports whether the socket is bound to every interface.
+func (p PortInfo) IsWildcard() bool {
+	ip := stdnet.ParseIP(p.Address)
+	return p.Address == "" || p.Address == "*" || (ip != nil && ip.IsUnspecified())
+}
+
+// portKey identifies one listening socket. The same port can be bound
+// separately per protocol, address family and bind address.
+type portKey struct {
+	protocol string
+	family   string
+	address  string
+	port     int
+}
+
+type KillResult struct {
+	Port int `json:"port"`
+	// Ports lists every port the process held; Port is the first of them.
+	Ports       []int  `json:"ports"`
+	PID         int32  `json:"pid"`
+	ProcessName string `json:"process_name"`
+	Killed      bool   `json:"killed"`
+	// Container is set when the container was stopped instead of the
+	// port owner; the other fields then describe the container's init.
+	Container *ContainerInfo `json:"container,omitempty"`
+	// Signal is the one the process exited on; Escalated is set when it
+	// outlived the grace period and needed SIGKILL.
+	Signal    string `json:"signal,omitempty"`
+	Escalated bool   `json:"escalated"`
+	Error     string `json:"error,omitempty"`
+	// Related lists the other processes of a --tree or --group kill.
+	Related []ProcessKill `json:"related,omitempty"`
+}
+
+type ProcessKill struct {
+	PID         int32  `json:"pid"`
+	ProcessName string `json:"process_name"`
+	Signal      string `json:"signal,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// KillSelector picks the listening ports `csys ports kill` acts on. Every
+// set field must match; zero fields match everything.
+type KillSelector struct {
+	Ranges []PortRange
+	// Name matches the process name exactly, ignoring case.
+	Name string
+	// AllDev keeps unprivileged ports (1024+) owned by the current user.
+	AllDev   bool
+	Protocol string
+}
+
+func (s KillSelector) matches(p PortInfo) bool {
+	if len(s.Ranges) > 0 {
+		inRange := false
+		for _, r := range s.Ranges {
+			if r.Contains(p.Port) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+	if s.Name != "" && !strings.EqualFold(p.ProcessName, s.Name) {
+		return false
+	}
+	if s.AllDev && (p.Port < 1024 || p.UID != int32(os.Getuid())) {
+		return false
+	}
+	if s.Protocol != "" && p.Protocol != s.Protocol {
+		return false
+	}
+	return true
+}
+
+// KillTarget is one process and every selected port it holds. Identity is
+// captured when the target is selected, so the process signalled is the one
+// shown in the confirmation.
+type KillTarget struct {
+	PID         int32           `json:"pid"`
+	ProcessName string          `json:"process_name"`
+	User        string          `json:"user"`
+	Identity    ProcessIdentity `json:"identity"`
+	Ports       []PortInfo      `json:"ports"`
+	// Tree holds the owner and related processes for --tree and --group,
+	// in display order; empty when only the owner is signalled.
+	Tree      []TreeProcess  `json:"tree,omitempty"`
+	Container *ContainerInfo `json:"container,omitempty"`
+	// StopContainer means Tree is the container's processes, which are
+	// signalled instead of the owner when it is a docker-proxy.
+	StopContainer bool `json:"stop_container,omitempty"`
+}
+
+func (t KillTarget) PortNumbers() []int {
+	numbers := make([]int, 0, len(t.Ports))
+	for _, p := range t.Ports {
+		if len(numbers) == 0 || numbers[len(numbers)-1] != p.Port {
+			numbers = append(numbers, p.Port)
+		}
+	}
+	return numbers
+}
+
+// SelectKillTargets groups the matching ports by owning process, so a server
+// bound to several ports is signalled once. Sockets whose owner is unknown
+// (PID 0) each get their own target.
+func SelectKillTargets(ports []PortInfo, sel KillSelector) []KillTarget {
+	targets := make([]KillTarget, 0)
+	byPID := make(map[int32]int)
+
+	for _, p := range ports {
+		if !sel.matches(p) {
+			continue
+		}
+		if i, ok := byPID[p.PID]; ok && p.PID != 0 {
+			targets[i].Ports = append(targets[i].Ports, p)
+			continue
+		}
+		byPID[p.PID] = len(targets)
+		targets = append(targets, KillTarget{
+			PID:         p.PID,
+			ProcessName: p.ProcessName,
+			User:        p.User,
+			Identity:    identifyPortOwner(p),
+			Ports:       []PortInfo{p},
+			Container:   p.Container,
+		})
+	}
+
+	return targets
+}
+
+// GetListeningPorts returns TCP sockets in LISTEN state and bound,
+// unconnected UDP sockets, one entry per protocol/family/address/port.
 func GetListeningPorts() ([]PortInfo, error) {
-	conns, err := net.Connections("all")
+	conns, err := net.Connections("inet")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get connections: %w", err)
 	}
 
-	var ports []PortInfo
-	seen := make(map[int]bool)
+	ports := make([]PortInfo, 0)
+	seen := make(map[portKey]bool)
+	procs := newProcessCache()
 
 	for _, conn := range conns {
-		if conn.Status != "LISTEN" {
-			continue
+		protocol := socketProtocol(conn.Type)
+
+		state := conn.Status
+		switch protocol {
+		case "tcp":
+			if conn.Status != "LISTEN" {
+				continue
+			}
+		case "udp":
+			// UDP has no LISTEN state; a socket with no peer is a bound server.
+			if conn.Raddr.Port != 0 {
+				continue
+			}
+			state = "BOUND"
 		}
 
 		port := int(conn.Laddr.Port)
-		if port == 0 || seen[port] {
+		if port == 0 {
 			continue
 		}
-		seen[port] = true
-
-		processName := "unknown"
-		memory := uint64(0)
-
-		if conn.Pid != 0 {
-			p, err := process.NewProcess(conn.Pid)
-			if err == nil {
-				if name, err := p.Name(); err == nil {
-					processName = name
-				}
-				if memInfo, err := p.MemoryInfo(); err == nil {
-					memory = memInfo.RSS
-				}
-			}
-		}
 
-		protocol := "tcp"
-		if conn.Type == 17 {
-			protocol = "udp"
+		key := portKey{
+			protocol: protocol,
+			family:   socketFamily(conn.Family),
+			address:  conn.Laddr.IP,
+			port:     port,
+		}
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
+
+		processName, memory := procs.lookup(conn.Pid)
+		user, uid := procs.owner(conn.Pid)
 
 		ports = append(ports, PortInfo{
 			Port:        port,
 			Protocol:    protocol,
-			State:       conn.Status,
+			Family:      key.family,
+			Address:     key.address,
+			State:       state,
 			ProcessName: processName,
 			PID:         conn.Pid,
 			Memory:      memory,
+			User:        user,
+			UID:         uid,
+			Container:   procs.container(conn.Pid),
 		})
 	}
 
-	return ports, nil
-}
-
-func GetProcessOnPort(port int) (*PortInfo, error) {
-	ports, err := GetListeningPorts()
-	if err != nil {
-		return nil, err
-	}
-
-	for _, p := range ports {
-		if p.Port == port {
-			return &p, nil
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
 		}
-	}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		return a.Address < b.Address
+	})
 
-	return nil, fmt.Errorf("port %d not found", port)
+	return ports, nil
 }
 
-func KillProcessOnPort(port int, force bool) error {
-	portInfo, err := GetProcessOnPort(port)
-	if err != nil {
-		return err
+// identifyPortOwner pins the process that owned the socket. If the PID no
+// longer runs a process of the same name, it was recycled since the socket
+// table was read and the zero identity makes TerminateProcesses refuse it.
+func identifyPortOwner(p PortInfo) ProcessIdentity {
+	if p.PID == 0 {
+		return ProcessIdentity{}
 	}
-
-	if portInfo.PID == 0 {
-		return fmt.Errorf("no process found for port %d", port)
-	}
-
-	pid := int(portInfo.PID)
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("failed to find process %d: %w", pid, err)
+	id, err := IdentifyProcess(p.PID)
+	if err != nil || id.Name != p.ProcessName {
+		return ProcessIdentity{PID: p.PID, Name: p.ProcessName}
 	}
-
-	if force {
-		err = proc.Signal(syscall.SIGKILL)
-	} else {
-		err = proc.Signal(syscall.SIGTERM)
-		if err == nil {
-			time.Sleep(1 * time.Second)
-			if proc2, err2 := os.FindProcess(pid); err2 == nil {
-				if err3 := proc2.Signal(syscall.Signal(0)); err3 == nil {
-					proc.Signal(syscall.SIGKILL)
-				}
-			}
-		}
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to kill process %d: %w", pid, err)
-	}
-
-	return nil
+	return id
 }
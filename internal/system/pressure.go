@@ -0,0 +1,117 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PressureThresholds colour stall percentages. Stalls are the share of time
+// tasks waited on a resource, so far lower values than utilisation already
+// mean the machine is struggling.
+var PressureThresholds = Thresholds{Warning: 10, Critical: 30}
+
+// PressureStats is one line of a /proc/pressure file: the percentage of
+// time tasks were stalled, averaged over 10s, 60s and 300s, and the total
+// stall time in microseconds since boot.
+type PressureStats struct {
+	Avg10   float64 `json:"avg10"`
+	Avg60   float64 `json:"avg60"`
+	Avg300  float64 `json:"avg300"`
+	TotalUs uint64  `json:"total_us"`
+}
+
+// ResourcePressure is "some" (at least one task stalled) and "full" (every
+// non-idle task stalled at once). Full is nil where the kernel does not
+// report it.
+type ResourcePressure struct {
+	Some PressureStats  `json:"some"`
+	Full *PressureStats `json:"full,omitempty"`
+}
+
+// PressureInfo is Linux Pressure Stall Information: whether work is waiting
+// on CPU, memory or I/O, which utilisation alone does not show.
+type PressureInfo struct {
+	CPU    ResourcePressure `json:"cpu"`
+	Memory ResourcePressure `json:"memory"`
+	IO     ResourcePressure `json:"io"`
+}
+
+const pressureRoot = "/proc/pressure"
+
+// GetPressure reads /proc/pressure. It fails on kernels built without PSI
+// (or booted with psi=0) and on other platforms.
+func GetPressure() (*PressureInfo, error) {
+	info := &PressureInfo{}
+	for _, r := range []struct {
+		file string
+		dest *ResourcePressure
+	}{
+		{"cpu", &info.CPU},
+		{"memory", &info.Memory},
+		{"io", &info.IO},
+	} {
+		data, err := os.ReadFile(filepath.Join(pressureRoot, r.file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pressure stall information: %w", err)
+		}
+		p, err := parsePressure(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s pressure: %w", r.file, err)
+		}
+		*r.dest = p
+	}
+	return info, nil
+}
+
+// parsePressure reads lines like
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+func parsePressure(data string) (ResourcePressure, error) {
+	var p ResourcePressure
+	seenSome := false
+
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stats PressureStats
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return p, fmt.Errorf("malformed field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				stats.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.TotalUs, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return p, fmt.Errorf("malformed field %q", field)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			p.Some = stats
+			seenSome = true
+		case "full":
+			p.Full = &stats
+		}
+	}
+
+	if !seenSome {
+		return p, fmt.Errorf("no \"some\" line")
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ResourcePressure
+		wantErr bool
+	}{
+		{
+			name: "some and full",
+			data: "some avg10=1.53 avg60=0.87 avg300=0.32 total=6543210\nfull avg10=0.40 avg60=0.11 avg300=0.02 total=123456\n",
+			want: ResourcePressure{
+				Some: PressureStats{Avg10: 1.53, Avg60: 0.87, Avg300: 0.32, TotalUs: 6543210},
+				Full: &PressureStats{Avg10: 0.40, Avg60: 0.11, Avg300: 0.02, TotalUs: 123456},
+			},
+		},
+		{
+			// CPU pressure had no full line before Linux 5.13.
+			name: "no full line",
+			data: "some avg10=12.00 avg60=8.50 avg300=3.25 total=98765\n",
+			want: ResourcePressure{
+				Some: PressureStats{Avg10: 12, Avg60: 8.5, Avg300: 3.25, TotalUs: 98765},
+			},
+		},
+		{
+			name: "idle",
+			data: "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			want: ResourcePressure{Full: &PressureStats{}},
+		},
+		{
+			name:    "only full",
+			data:    "full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			data:    "",
+			wantErr: true,
+		},
+		{
+			name:    "malformed field",
+			data:    "some avg10 avg60=0.00 avg300=0.00 total=0\n",
+			wantErr: true,
+		},
+		{
+			name:    "malformed value",
+			data:    "some avg10=high avg60=0.00 avg300=0.00 total=0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePressure(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePressure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePressure() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
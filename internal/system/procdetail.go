@@ -0,0 +1,229 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessRef names one process, e.g. an ancestor in a parent chain.
+type ProcessRef struct {
+	PID  int32  `json:"pid"`
+	Name string `json:"name"`
+}
+
+type ProcessDetailOptions struct {
+	// Window is how long CPU usage is measured for.
+	Window time.Duration
+	// Env includes the environment, which often holds secrets.
+	Env bool
+}
+
+// FindProcesses resolves a PID or a process name (exact, ignoring case) to
+// the matching PIDs, lowest first.
+func FindProcesses(query string) ([]int32, error) {
+	if pid, err := strconv.ParseInt(query, 10, 32); err == nil {
+		if exists, _ := process.PidExists(int32(pid)); !exists {
+			return nil, fmt.Errorf("no process with PID %d", pid)
+		}
+		return []int32{int32(pid)}, nil
+	}
+
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var pids []int32
+	for _, p := range procs {
+		if name, err := p.Name(); err == nil && strings.EqualFold(name, query) {
+			pids = append(pids, p.Pid)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no process named %q", query)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids, nil
+}
+
+// GetProcessDetails inspects each PID in depth. CPU% is measured over one
+// shared window. Processes that exit before they are read are left out.
+func GetProcessDetails(pids []int32, opts ProcessDetailOptions) ([]ProcessInfo, error) {
+	type sample struct {
+		p     *process.Process
+		info  ProcessInfo
+		total float64
+	}
+
+	samples := make([]sample, 0, len(pids))
+	for _, pid := range pids {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			continue
+		}
+		info, ok := inspectProcess(p)
+		if !ok {
+			continue
+		}
+		s := sample{p: p, info: info}
+		if times, err := p.Times(); err == nil {
+			s.total = times.User + times.System
+		}
+		samples = append(samples, s)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("failed to inspect process %d: access denied or exited", pids[0])
+	}
+
+	start := time.Now()
+	time.Sleep(opts.Window)
+	elapsed := time.Since(start).Seconds()
+
+	sockets := processSockets(pids)
+	containers := NewContainerResolver()
+	details := make([]ProcessInfo, 0, len(samples))
+	for _, s := range samples {
+		info := s.info
+		if times, err := s.p.Times(); err == nil && elapsed > 0 {
+			info.CPUPercent = (times.User + times.System - s.total) / elapsed * 100
+		}
+		fillProcessDetail(s.p, &info, opts)
+		info.Sockets = sockets[info.PID]
+		info.Container = containers.ForPID(info.PID)
+		details = append(details, info)
+	}
+	return details, nil
+}
+
+// fillProcessDetail adds the fields only `csys proc` shows. Everything is
+// best effort: other users' processes hide their cwd and environment
+// unless csys runs as root.
+func fillProcessDetail(p *process.Process, info *ProcessInfo, opts ProcessDetailOptions) {
+	info.Cmdline, _ = p.Cmdline()
+	info.Cwd, _ = p.Cwd()
+	info.Exe, _ = p.Exe()
+	info.User, _ = p.Username()
+	if nice, err := processNice(p); err == nil {
+		info.Nice = &nice
+	}
+	info.Cgroup = readCgroup(p.Pid)
+
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		info.Status = status[0]
+	}
+	if mem, err := p.MemoryInfo(); err == nil {
+		info.Swap = mem.Swap
+	}
+
+	seen := map[int32]bool{info.PID: true}
+	for ppid := info.PPID; ppid > 0 && !seen[ppid]; {
+		seen[ppid] = true
+		parent, err := process.NewProcess(ppid)
+		if err != nil {
+			break
+		}
+		name, _ := parent.Name()
+		info.Ancestors = append(info.Ancestors, ProcessRef{PID: ppid, Name: name})
+		if ppid, err = parent.Ppid(); err != nil {
+			break
+		}
+	}
+
+	if opts.Env {
+		info.Env, _ = p.Environ()
+	}
+}
+
+// processSockets returns the sockets of each PID: listeners first, then
+// connections by state. Unconnected UDP sockets are reported as BOUND.
+func processSockets(pids []int32) map[int32][]ConnectionInfo {
+	wanted := make(map[int32]bool, len(pids))
+	for _, pid := range pids {
+		wanted[pid] = true
+	}
+
+	sockets := make(map[int32][]ConnectionInfo)
+	conns, err := net.Connections("inet")
+	if err != nil {
+		return sockets
+	}
+
+	for _, conn := range conns {
+		if !wanted[conn.Pid] {
+			continue
+		}
+		state := conn.Status
+		if state == "" || state == "NONE" {
+			if conn.Raddr.Port != 0 {
+				continue
+			}
+			state = "BOUND"
+		}
+		sockets[conn.Pid] = append(sockets[conn.Pid], ConnectionInfo{
+			Protocol:   socketProtocol(conn.Type),
+			Family:     socketFamily(conn.Family),
+			LocalAddr:  conn.Laddr.IP,
+			LocalPort:  int(conn.Laddr.Port),
+			RemoteAddr: conn.Raddr.IP,
+			RemotePort: int(conn.Raddr.Port),
+			State:      state,
+			PID:        conn.Pid,
+		})
+	}
+
+	for _, list := range sockets {
+		sort.Slice(list, func(i, j int) bool {
+			a, b := list[i], list[j]
+			if socketRank(a.State) != socketRank(b.State) {
+				return socketRank(a.State) < socketRank(b.State)
+			}
+			if a.State != b.State {
+				return a.State < b.State
+			}
+			return a.LocalPort < b.LocalPort
+		})
+	}
+	return sockets
+}
+
+func socketRank(state string) int {
+	switch state {
+	case "LISTEN", "BOUND":
+		return 0
+	case "ESTABLISHED":
+		return 1
+	}
+	return 2
+}
+
+// readCgroup returns the process's cgroup path from /proc/<pid>/cgroup: the
+// unified (v2) entry if present, else the first v1 hierarchy. Empty where
+// cgroups do not exist.
+func readCgroup(pid int32) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+
+	var first string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+	return first
+}
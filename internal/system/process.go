@@ -1,50 +1,248 @@
 package system
 
 import (
+	"fmt"
+	"os"
 	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
 
 type ProcessInfo struct {
-	PID    int32
-	Name   string
-	Memory uint64
+	PID        int32     `json:"pid"`
+	Name       string    `json:"name"`
+	Memory     uint64    `json:"memory_bytes"`
+	CPUPercent float64   `json:"cpu_percent"`
+	VMS        uint64    `json:"virtual_memory_bytes"`
+	Threads    int32     `json:"threads"`
+	OpenFiles  int32     `json:"open_files"`
+	StartTime  time.Time `json:"start_time"`
+	PPID       int32     `json:"ppid"`
+	// Container is set by AttachContainers for processes in a container.
+	Container *ContainerInfo `json:"container,omitempty"`
+
+	// Detail fields, filled in by GetProcessDetails. GroupProcesses also
+	// fills in the one it groups by.
+	Status    string           `json:"status,omitempty"`
+	Cmdline   string           `json:"cmdline,omitempty"`
+	Exe       string           `json:"exe,omitempty"`
+	Cwd       string           `json:"cwd,omitempty"`
+	User      string           `json:"user,omitempty"`
+	Ancestors []ProcessRef     `json:"ancestors,omitempty"`
+	Swap      uint64           `json:"swap_bytes,omitempty"`
+	Nice      *int32           `json:"nice,omitempty"`
+	Cgroup    string           `json:"cgroup,omitempty"`
+	Sockets   []ConnectionInfo `json:"sockets,omitempty"`
+	Env       []string         `json:"env,omitempty"`
+}
+
+type ProcessSortKey string
+
+const (
+	SortByCPU     ProcessSortKey = "cpu"
+	SortByRSS     ProcessSortKey = "rss"
+	SortByVMS     ProcessSortKey = "vms"
+	SortByThreads ProcessSortKey = "threads"
+	SortByFiles   ProcessSortKey = "files"
+	SortByStart   ProcessSortKey = "start"
+	SortByPID     ProcessSortKey = "pid"
+)
+
+var ProcessSortKeys = []ProcessSortKey{
+	SortByCPU, SortByRSS, SortByVMS, SortByThreads, SortByFiles, SortByStart, SortByPID,
+}
+
+func ParseProcessSortKey(s string) (ProcessSortKey, error) {
+	switch strings.ToLower(s) {
+	case "mem", "memory":
+		return SortByRSS, nil
+	case "fds", "open-files":
+		return SortByFiles, nil
+	case "started", "start-time":
+		return SortByStart, nil
+	}
+
+	for _, key := range ProcessSortKeys {
+		if ProcessSortKey(strings.ToLower(s)) == key {
+			return key, nil
+		}
+	}
+
+	names := make([]string, len(ProcessSortKeys))
+	for i, key := range ProcessSortKeys {
+		names[i] = string(key)
+	}
+	return "", fmt.Errorf("invalid sort key %q (must be one of %s)", s, strings.Join(names, ", "))
+}
+
+// SortProcesses orders procs by key: largest first for resource usage,
+// newest first for start time and ascending for PID.
+func SortProcesses(procs []ProcessInfo, key ProcessSortKey) {
+	sort.SliceStable(procs, func(i, j int) bool {
+		return processLess(procs[i], procs[j], key)
+	})
+}
+
+func processLess(a, b ProcessInfo, key ProcessSortKey) bool {
+	switch key {
+	case SortByCPU:
+		return a.CPUPercent > b.CPUPercent
+	case SortByVMS:
+		return a.VMS > b.VMS
+	case SortByThreads:
+		return a.Threads > b.Threads
+	case SortByFiles:
+		return a.OpenFiles > b.OpenFiles
+	case SortByStart:
+		return a.StartTime.After(b.StartTime)
+	case SortByPID:
+		return a.PID < b.PID
+	}
+	return a.Memory > b.Memory
+}
+
+type ProcessQuery struct {
+	SortBy ProcessSortKey
+	// Limit caps the number of results; 0 returns every process.
+	Limit int
+	// Window is how long CPU time is measured for before computing CPU%.
+	Window time.Duration
+}
+
+func QueryProcesses(q ProcessQuery) ([]ProcessInfo, error) {
+	sampler := NewProcessSampler()
+	if _, err := sampler.Sample(); err != nil {
+		return nil, err
+	}
+	time.Sleep(q.Window)
+
+	procs, err := sampler.Sample()
+	if err != nil {
+		return nil, err
+	}
+
+	SortProcesses(procs, q.SortBy)
+
+	if q.Limit > 0 && len(procs) > q.Limit {
+		return procs[:q.Limit], nil
+	}
+	return procs, nil
+}
+
+type processCPU struct {
+	total     float64
+	startTime time.Time
+}
+
+// ProcessSampler lists processes and computes each one's CPU% from the CPU
+// time it used since the previous Sample. 100% means one full core.
+type ProcessSampler struct {
+	prev map[int32]processCPU
+	last time.Time
+}
+
+func NewProcessSampler() *ProcessSampler {
+	return &ProcessSampler{prev: make(map[int32]processCPU)}
 }
 
-func GetTopProcessesByMemory(count int) ([]ProcessInfo, error) {
+// Sample returns every process that can be inspected, in no particular
+// order. Processes that exit or deny access mid-scan are skipped. CPUPercent
+// is zero for processes not seen by the previous Sample.
+func (s *ProcessSampler) Sample() ([]ProcessInfo, error) {
 	procs, err := process.Processes()
 	if err != nil {
 		return nil, err
 	}
 
-	var procInfos []ProcessInfo
+	now := time.Now()
+	elapsed := now.Sub(s.last).Seconds()
+	next := make(map[int32]processCPU, len(procs))
+	procInfos := make([]ProcessInfo, 0, len(procs))
 
 	for _, p := range procs {
-		name, err := p.Name()
-		if err != nil {
+		info, ok := inspectProcess(p)
+		if !ok {
 			continue
 		}
 
-		memInfo, err := p.MemoryInfo()
-		if err != nil {
-			continue
+		if times, err := p.Times(); err == nil {
+			cur := processCPU{total: times.User + times.System, startTime: info.StartTime}
+			next[p.Pid] = cur
+
+			// A matching start time guards against the PID having been reused.
+			prev, seen := s.prev[p.Pid]
+			if seen && prev.startTime.Equal(cur.startTime) && !s.last.IsZero() && elapsed > 0 {
+				info.CPUPercent = (cur.total - prev.total) / elapsed * 100
+			}
 		}
 
-		procInfos = append(procInfos, ProcessInfo{
-			PID:    p.Pid,
-			Name:   name,
-			Memory: memInfo.RSS,
-		})
+		procInfos = append(procInfos, info)
 	}
 
-	sort.Slice(procInfos, func(i, j int) bool {
-		return procInfos[i].Memory > procInfos[j].Memory
-	})
+	s.prev = next
+	s.last = now
 
-	if len(procInfos) > count {
-		return procInfos[:count], nil
+	return procInfos, nil
+}
+
+func inspectProcess(p *process.Process) (ProcessInfo, bool) {
+	name, err := p.Name()
+	if err != nil {
+		return ProcessInfo{}, false
 	}
 
-	return procInfos, nil
+	memInfo, err := p.MemoryInfo()
+	if err != nil {
+		return ProcessInfo{}, false
+	}
+
+	info := ProcessInfo{
+		PID:    p.Pid,
+		Name:   name,
+		Memory: memInfo.RSS,
+		VMS:    memInfo.VMS,
+	}
+
+	// The remaining fields are best effort; some need elevated privileges.
+	if threads, err := p.NumThreads(); err == nil {
+		info.Threads = threads
+	}
+	if fds, err := p.NumFDs(); err == nil {
+		info.OpenFiles = fds
+	}
+	if created, err := p.CreateTime(); err == nil {
+		info.StartTime = time.UnixMilli(created)
+	}
+	if ppid, err := p.Ppid(); err == nil {
+		info.PPID = ppid
+	}
+
+	return info, true
+}
+
+func topProcesses(procs []ProcessInfo, key ProcessSortKey, count int) []ProcessInfo {
+	sorted := make([]ProcessInfo, len(procs))
+	copy(sorted, procs)
+	SortProcesses(sorted, key)
+
+	if len(sorted) > count {
+		return sorted[:count]
+	}
+	return sorted
+}
+
+func SignalProcess(pid int32, sig syscall.Signal) error {
+	proc, err := os.FindProcess(int(pid))
+	if err != nil {
+		return fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+
+	if err := proc.Signal(sig); err != nil {
+		return fmt.Errorf("failed to signal process %d: %w", pid, err)
+	}
+
+	return nil
 }
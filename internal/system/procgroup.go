@@ -0,0 +1,179 @@
+package system
+
+import (
+	"fmt"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessGroupKey is what processes are aggregated by.
+type ProcessGroupKey string
+
+const (
+	GroupByNone   ProcessGroupKey = ""
+	GroupByName   ProcessGroupKey = "name"
+	GroupByExe    ProcessGroupKey = "exe"
+	GroupByUser   ProcessGroupKey = "user"
+	GroupByCgroup ProcessGroupKey = "cgroup"
+)
+
+var ProcessGroupKeys = []ProcessGroupKey{GroupByName, GroupByExe, GroupByUser, GroupByCgroup}
+
+func ParseProcessGroupKey(s string) (ProcessGroupKey, error) {
+	for _, key := range ProcessGroupKeys {
+		if ProcessGroupKey(strings.ToLower(s)) == key {
+			return key, nil
+		}
+	}
+
+	names := make([]string, len(ProcessGroupKeys))
+	for i, key := range ProcessGroupKeys {
+		names[i] = string(key)
+	}
+	return "", fmt.Errorf("invalid group key %q (must be one of %s)", s, strings.Join(names, ", "))
+}
+
+// ProcessGroup sums the processes sharing one name, executable, user or
+// cgroup, e.g. every chrome helper as one "chrome" row.
+type ProcessGroup struct {
+	Key        string  `json:"key"`
+	Processes  int     `json:"processes"`
+	Memory     uint64  `json:"memory_bytes"`
+	CPUPercent float64 `json:"cpu_percent"`
+	PIDs       []int32 `json:"pids"`
+}
+
+// GroupProcesses aggregates procs by key, largest memory first. The field
+// a key needs (exe, user or cgroup) is looked up and stored on procs.
+func GroupProcesses(procs []ProcessInfo, key ProcessGroupKey) []ProcessGroup {
+	annotateProcesses(procs, key)
+
+	byKey := make(map[string]*ProcessGroup)
+	for _, p := range procs {
+		k := ProcessGroupValue(p, key)
+		g, ok := byKey[k]
+		if !ok {
+			g = &ProcessGroup{Key: k}
+			byKey[k] = g
+		}
+		g.Processes++
+		g.Memory += p.Memory
+		g.CPUPercent += p.CPUPercent
+		g.PIDs = append(g.PIDs, p.PID)
+	}
+
+	groups := make([]ProcessGroup, 0, len(byKey))
+	for _, g := range byKey {
+		sort.Slice(g.PIDs, func(i, j int) bool { return g.PIDs[i] < g.PIDs[j] })
+		groups = append(groups, *g)
+	}
+	SortProcessGroups(groups, SortByRSS)
+	return groups
+}
+
+// SortProcessGroups orders groups by total CPU% for SortByCPU and by total
+// memory for every other key; ties go by group name.
+func SortProcessGroups(groups []ProcessGroup, key ProcessSortKey) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if key == SortByCPU && a.CPUPercent != b.CPUPercent {
+			return a.CPUPercent > b.CPUPercent
+		}
+		if a.Memory != b.Memory {
+			return a.Memory > b.Memory
+		}
+		return a.Key < b.Key
+	})
+}
+
+// FilterProcessGroup keeps the processes whose group under key is value,
+// for drilling into one group.
+func FilterProcessGroup(procs []ProcessInfo, key ProcessGroupKey, value string) []ProcessInfo {
+	annotateProcesses(procs, key)
+
+	filtered := make([]ProcessInfo, 0)
+	for _, p := range procs {
+		if strings.EqualFold(ProcessGroupValue(p, key), value) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// ProcessGroupValue returns the group p belongs to. Processes whose
+// executable cannot be read (other users' without root) fall back to their
+// name; unknown users and cgroups group under "-".
+func ProcessGroupValue(p ProcessInfo, key ProcessGroupKey) string {
+	switch key {
+	case GroupByExe:
+		if p.Exe != "" {
+			return p.Exe
+		}
+		return p.Name
+	case GroupByUser:
+		return orUnknown(p.User)
+	case GroupByCgroup:
+		return orUnknown(p.Cgroup)
+	}
+	return p.Name
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// annotateProcesses fills in the detail field key groups by, where missing.
+func annotateProcesses(procs []ProcessInfo, key ProcessGroupKey) {
+	users := make(map[int32]string)
+
+	for i := range procs {
+		p := &procs[i]
+		switch key {
+		case GroupByExe:
+			if p.Exe == "" {
+				if proc, err := process.NewProcess(p.PID); err == nil {
+					p.Exe, _ = proc.Exe()
+				}
+			}
+		case GroupByUser:
+			if p.User == "" {
+				p.User = lookupProcessUser(p.PID, users)
+			}
+		case GroupByCgroup:
+			if p.Cgroup == "" {
+				p.Cgroup = readCgroup(p.PID)
+			}
+		}
+	}
+}
+
+// lookupProcessUser resolves the real UID to a name once per UID; user
+// lookups read /etc/passwd (or query the directory service) every time.
+func lookupProcessUser(pid int32, cache map[int32]string) string {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return ""
+	}
+	uids, err := proc.Uids()
+	if err != nil || len(uids) == 0 {
+		return ""
+	}
+
+	uid := uids[0]
+	if name, ok := cache[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	cache[uid] = name
+	return name
+}
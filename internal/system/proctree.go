@@ -0,0 +1,69 @@
+package system
+
+import "sort"
+
+// ProcessNode is a process with its children and the resources used by the
+// whole subtree, which is what an application spread over helper processes
+// (browsers, Electron, language servers) really costs.
+type ProcessNode struct {
+	ProcessInfo
+	SubtreeMemory     uint64         `json:"subtree_memory_bytes"`
+	SubtreeCPUPercent float64        `json:"subtree_cpu_percent"`
+	Descendants       int            `json:"descendants"`
+	Children          []*ProcessNode `json:"children,omitempty"`
+}
+
+// BuildProcessTree links procs by PPID and returns the roots: processes whose
+// parent is not in procs. Siblings are ordered by key, using subtree totals
+// for CPU and memory.
+func BuildProcessTree(procs []ProcessInfo, key ProcessSortKey) []*ProcessNode {
+	nodes := make(map[int32]*ProcessNode, len(procs))
+	for _, p := range procs {
+		nodes[p.PID] = &ProcessNode{ProcessInfo: p}
+	}
+
+	roots := make([]*ProcessNode, 0)
+	for _, p := range procs {
+		node := nodes[p.PID]
+		parent, ok := nodes[p.PPID]
+		if !ok || p.PPID == p.PID {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, root := range roots {
+		sumSubtree(root)
+	}
+	sortProcessNodes(roots, key)
+	return roots
+}
+
+func sumSubtree(n *ProcessNode) {
+	n.SubtreeMemory = n.Memory
+	n.SubtreeCPUPercent = n.CPUPercent
+	n.Descendants = 0
+	for _, c := range n.Children {
+		sumSubtree(c)
+		n.SubtreeMemory += c.SubtreeMemory
+		n.SubtreeCPUPercent += c.SubtreeCPUPercent
+		n.Descendants += c.Descendants + 1
+	}
+}
+
+func sortProcessNodes(nodes []*ProcessNode, key ProcessSortKey) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		switch key {
+		case SortByCPU:
+			return a.SubtreeCPUPercent > b.SubtreeCPUPercent
+		case SortByRSS:
+			return a.SubtreeMemory > b.SubtreeMemory
+		}
+		return processLess(a.ProcessInfo, b.ProcessInfo, key)
+	})
+	for _, n := range nodes {
+		sortProcessNodes(n.Children, key)
+	}
+}
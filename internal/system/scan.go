@@ -8,26 +8,26 @@ import (
 )
 
 type FileItem struct {
-	Name      string
-	Path      string
-	Size      int64
-	IsDir     bool
-	Extension string
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size_bytes"`
+	IsDir     bool   `json:"is_dir"`
+	Extension string `json:"extension,omitempty"`
 }
 
 type TypeBreakdown struct {
-	Extension string
-	Size      int64
-	Count     int
+	Extension string `json:"extension"`
+	Size      int64  `json:"size_bytes"`
+	Count     int    `json:"count"`
 }
 
 type ScanResult struct {
-	RootPath      string
-	TotalSize     int64
-	FileCount     int
-	DirCount      int
-	Items         []FileItem
-	TypeBreakdown []TypeBreakdown
+	RootPath      string          `json:"root_path"`
+	TotalSize     int64           `json:"total_size_bytes"`
+	FileCount     int             `json:"file_count"`
+	DirCount      int             `json:"dir_count"`
+	Items         []FileItem      `json:"items"`
+	TypeBreakdown []TypeBreakdown `json:"type_breakdown"`
 }
 
 func ScanDirectory(path string) (*ScanResult, error) {
@@ -0,0 +1,114 @@
+package system
+
+import "time"
+
+type MetricSummary struct {
+	Min float64 `json:"min"`
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+}
+
+type SessionSummary struct {
+	Samples  int           `json:"samples"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration_ns"`
+	CPU      MetricSummary `json:"cpu_percent"`
+	Memory   MetricSummary `json:"memory_percent"`
+	Disk     MetricSummary `json:"disk_percent"`
+}
+
+// SessionRecorder accumulates overview samples taken during live mode so a
+// min/avg/max summary can be printed when the session ends.
+type SessionRecorder struct {
+	rxHistory []float64
+	txHistory []float64
+
+	started time.Time
+	last    time.Time
+	samples int
+	cpu     metricAccumulator
+	memory  metricAccumulator
+	disk    metricAccumulator
+}
+
+type metricAccumulator struct {
+	min, max, sum float64
+}
+
+func (a *metricAccumulator) add(v float64, first bool) {
+	if first || v < a.min {
+		a.min = v
+	}
+	if first || v > a.max {
+		a.max = v
+	}
+	a.sum += v
+}
+
+func (a *metricAccumulator) summary(n int) MetricSummary {
+	if n == 0 {
+		return MetricSummary{}
+	}
+	return MetricSummary{Min: a.min, Avg: a.sum / float64(n), Max: a.max}
+}
+
+func NewSessionRecorder() *SessionRecorder {
+	return &SessionRecorder{started: time.Now()}
+}
+
+func (r *SessionRecorder) Record(overview *Overview, at time.Time) {
+	first := r.samples == 0
+
+	r.cpu.add(overview.CPUPercent, first)
+	r.memory.add(overview.Memory.UsedPercent, first)
+
+	diskPercent := 0.0
+	if len(overview.Disk.Partitions) > 0 {
+		diskPercent = overview.Disk.Partitions[0].Percent
+	}
+	r.disk.add(diskPercent, first)
+
+	if overview.Network != nil {
+		r.rxHistory = appendHistory(r.rxHistory, overview.Network.RxBytesPerSec)
+		r.txHistory = appendHistory(r.txHistory, overview.Network.TxBytesPerSec)
+	}
+
+	r.samples++
+	r.last = at
+}
+
+// historyLength is how many recent samples are kept for sparklines.
+const historyLength = 30
+
+func appendHistory(history []float64, v float64) []float64 {
+	history = append(history, v)
+	if len(history) > historyLength {
+		history = history[len(history)-historyLength:]
+	}
+	return history
+}
+
+// NetHistory returns the most recent receive and transmit rates, oldest first.
+func (r *SessionRecorder) NetHistory() (rx, tx []float64) {
+	return r.rxHistory, r.txHistory
+}
+
+func (r *SessionRecorder) Samples() int {
+	return r.samples
+}
+
+func (r *SessionRecorder) Summary() SessionSummary {
+	end := r.last
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	return SessionSummary{
+		Samples:  r.samples,
+		Started:  r.started,
+		Duration: end.Sub(r.started),
+		CPU:      r.cpu.summary(r.samples),
+		Memory:   r.memory.summary(r.samples),
+		Disk:     r.disk.summary(r.samples),
+	}
+}
@@ -0,0 +1,170 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ParseSignal accepts a name with or without the SIG prefix, in any case
+// (TERM, sigint, SIGKILL), or a signal number.
+func ParseSignal(s string) (syscall.Signal, error) {
+	name := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "SIG")
+	for _, n := range signalNames {
+		if n.name == name {
+			return n.sig, nil
+		}
+	}
+	if num, err := strconv.Atoi(name); err == nil && num > 0 && num < 65 {
+		return syscall.Signal(num), nil
+	}
+	names := make([]string, len(signalNames))
+	for i, n := range signalNames {
+		names[i] = n.name
+	}
+	return 0, fmt.Errorf("unknown signal %q (use %s or a number)", s, strings.Join(names, ", "))
+}
+
+// SignalName returns the conventional name, e.g. SIGTERM.
+func SignalName(sig syscall.Signal) string {
+	for _, n := range signalNames {
+		if n.sig == sig {
+			return "SIG" + n.name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
+
+type KillOptions struct {
+	// Signal is sent first; SIGTERM when zero.
+	Signal syscall.Signal
+	// Grace is how long the process gets to exit before SIGKILL follows.
+	Grace time.Duration
+}
+
+const exitPollInterval = 100 * time.Millisecond
+
+// killWait is how long a process gets to disappear after SIGKILL, which
+// cannot be caught but is delayed by uninterruptible I/O.
+const killWait = 2 * time.Second
+
+// SignalIdentified sends sig once to the identified process through the same
+// verified handle as TerminateProcesses, without waiting for it to exit.
+func SignalIdentified(id ProcessIdentity, sig syscall.Signal) error {
+	h, err := openProcess(id)
+	if err != nil {
+		return err
+	}
+	defer h.close()
+	return h.signal(sig)
+}
+
+// TerminateResult is the signal a process exited on, or why it could not be
+// stopped.
+type TerminateResult struct {
+	Signal syscall.Signal
+	Err    error
+}
+
+// TerminateProcesses sends opts.Signal to each identified process and waits
+// for them to exit, escalating to SIGKILL once the grace period runs out. A
+// PID that now belongs to a different process is refused. They are signalled
+// in the given order and share one grace period, so a tree of workers takes
+// no longer to stop than a single process.
+func TerminateProcesses(ids []ProcessIdentity, opts KillOptions) []TerminateResult {
+	sig := opts.Signal
+	if sig == 0 {
+		sig = syscall.SIGTERM
+	}
+
+	results := make([]TerminateResult, len(ids))
+	handles := make([]processHandle, len(ids))
+	defer func() {
+		for _, h := range handles {
+			if h != nil {
+				h.close()
+			}
+		}
+	}()
+
+	for i, id := range ids {
+		h, err := openProcess(id)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		handles[i] = h
+		if err := h.signal(sig); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Signal = sig
+	}
+
+	wait := opts.Grace
+	if sig == syscall.SIGKILL {
+		wait = killWait
+	}
+	deadline := time.Now().Add(wait)
+
+	var survivors []int
+	for i, h := range handles {
+		if results[i].Err != nil {
+			continue
+		}
+		if h.waitExit(time.Until(deadline)) {
+			continue
+		}
+		if sig == syscall.SIGKILL {
+			results[i] = TerminateResult{Err: fmt.Errorf("process %d did not exit after SIGKILL", ids[i].PID)}
+			continue
+		}
+		survivors = append(survivors, i)
+	}
+
+	for _, i := range survivors {
+		if err := handles[i].signal(syscall.SIGKILL); err != nil {
+			// It may have exited between the last poll and SIGKILL.
+			if !handles[i].waitExit(0) {
+				results[i] = TerminateResult{Err: err}
+			}
+			continue
+		}
+		results[i].Signal = syscall.SIGKILL
+	}
+
+	deadline = time.Now().Add(killWait)
+	for _, i := range survivors {
+		if results[i].Signal != syscall.SIGKILL {
+			continue
+		}
+		if !handles[i].waitExit(time.Until(deadline)) {
+			results[i] = TerminateResult{Err: fmt.Errorf("process %d did not exit after SIGKILL", ids[i].PID)}
+		}
+	}
+
+	return results
+}
+
+// processAlive treats zombies as gone: they hold no ports and only wait for
+// their parent to reap them.
+func processAlive(pid int32) bool {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	status, err := p.Status()
+	if err != nil {
+		return false
+	}
+	for _, s := range status {
+		if s == process.Zombie {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package system
+
+import "syscall"
+
+// signalNames are the signals syscall defines outside unix; there are no
+// user-defined signals.
+var signalNames = []struct {
+	name string
+	sig  syscall.Signal
+}{
+	{"TERM", syscall.SIGTERM},
+	{"INT", syscall.SIGINT},
+	{"HUP", syscall.SIGHUP},
+	{"QUIT", syscall.SIGQUIT},
+	{"KILL", syscall.SIGKILL},
+}
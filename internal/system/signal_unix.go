@@ -0,0 +1,20 @@
+//go:build unix
+
+package system
+
+import "syscall"
+
+// signalNames are the signals accepted by name, in the order they are listed
+// in help text.
+var signalNames = []struct {
+	name string
+	sig  syscall.Signal
+}{
+	{"TERM", syscall.SIGTERM},
+	{"INT", syscall.SIGINT},
+	{"HUP", syscall.SIGHUP},
+	{"QUIT", syscall.SIGQUIT},
+	{"KILL", syscall.SIGKILL},
+	{"USR1", syscall.SIGUSR1},
+	{"USR2", syscall.SIGUSR2},
+}
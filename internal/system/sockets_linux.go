@@ -0,0 +1,69 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tcpListen is the LISTEN state in /proc/net/tcp.
+const tcpListen = "0A"
+
+// listeningPortSet returns the ports with a listening socket of protocol,
+// read straight from /proc/net. Unlike GetListeningPorts it does not walk
+// every process's file descriptors, so it is cheap enough to poll.
+func listeningPortSet(protocol string) (map[int]bool, error) {
+	ports := make(map[int]bool)
+	for _, file := range []string{protocol, protocol + "6"} {
+		f, err := os.Open("/proc/net/" + file)
+		if os.IsNotExist(err) {
+			continue // IPv6 disabled
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sockets: %w", err)
+		}
+		parseProcNetSockets(f, protocol, ports)
+		f.Close()
+	}
+	return ports, nil
+}
+
+// parseProcNetSockets adds the listening ports in a /proc/net/tcp or udp
+// table to ports: TCP sockets in LISTEN and UDP sockets without a peer,
+// the same ones GetListeningPorts reports.
+func parseProcNetSockets(r io.Reader, protocol string, ports map[int]bool) {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		if protocol == "tcp" && fields[3] != tcpListen {
+			continue
+		}
+		if protocol == "udp" && procNetPort(fields[2]) != 0 {
+			continue
+		}
+		if port := procNetPort(fields[1]); port != 0 {
+			ports[port] = true
+		}
+	}
+}
+
+// procNetPort reads the port of an address like 0100007F:1F90.
+func procNetPort(addr string) int {
+	_, hex, ok := strings.Cut(addr, ":")
+	if !ok {
+		return 0
+	}
+	port, err := strconv.ParseUint(hex, 16, 16)
+	if err != nil {
+		return 0
+	}
+	return int(port)
+}
@@ -0,0 +1,41 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProcNetSockets(t *testing.T) {
+	tcp := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1111 1 0000000000000000 100 0 0 10 0
+   1: 00000000:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 2222 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 3333 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:C350 0100007F:1538 06 00000000:00000000 03:00000d2e 00000000     0        0 0 3 0000000000000000
+`
+	udp := `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  100: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 4444 2 0000000000000000 0
+  101: 0100007F:B76B 0100007F:0035 01 00000000:00000000 00:00000000 00000000  1000        0 5555 2 0000000000000000 0
+`
+
+	tests := []struct {
+		name     string
+		table    string
+		protocol string
+		want     map[int]bool
+	}{
+		{"tcp listeners only", tcp, "tcp", map[int]bool{8080: true, 5432: true}},
+		{"unconnected udp", udp, "udp", map[int]bool{53: true}},
+		{"header only", "  sl  local_address rem_address   st\n", "tcp", map[int]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[int]bool)
+			parseProcNetSockets(strings.NewReader(tt.table), tt.protocol, got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProcNetSockets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !linux
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// listeningPortSet returns the ports with a listening socket of protocol,
+// without looking up the processes that own them.
+func listeningPortSet(protocol string) (map[int]bool, error) {
+	conns, err := net.ConnectionsWithoutUids("inet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	ports := make(map[int]bool)
+	for _, conn := range conns {
+		if socketProtocol(conn.Type) != protocol || conn.Laddr.Port == 0 {
+			continue
+		}
+		if protocol == "tcp" && conn.Status != "LISTEN" {
+			continue
+		}
+		if protocol == "udp" && conn.Raddr.Port != 0 {
+			continue
+		}
+		ports[int(conn.Laddr.Port)] = true
+	}
+	return ports, nil
+}
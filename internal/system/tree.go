@@ -0,0 +1,323 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// KillScope says which processes besides the port owner a kill reaches.
+type KillScope int
+
+const (
+	// ScopeProcess signals only the process holding the socket.
+	ScopeProcess KillScope = iota
+	// ScopeTree adds every descendant of the owner.
+	ScopeTree
+	// ScopeGroup signals the owner's whole process group, which includes the
+	// shell or supervisor (npm, make, nodemon) a job was started from.
+	ScopeGroup
+	// ScopeContainer stops the container the owner runs in, or forwards to
+	// if it is a docker-proxy, by signalling every process in it. The proxy
+	// itself is left for the runtime to remove.
+	ScopeContainer
+)
+
+// TreeProcess is one process of a --tree or --group kill. Depth is its
+// distance from the topmost selected ancestor, for display.
+type TreeProcess struct {
+	Identity ProcessIdentity `json:"identity"`
+	PPID     int32           `json:"ppid"`
+	Depth    int             `json:"depth"`
+}
+
+// KillOrder returns the identities deepest first, so children are signalled
+// before the parents that might respawn them.
+func (t KillTarget) KillOrder() []ProcessIdentity {
+	if len(t.Tree) == 0 {
+		return []ProcessIdentity{t.Identity}
+	}
+
+	tree := make([]TreeProcess, len(t.Tree))
+	copy(tree, t.Tree)
+	sort.SliceStable(tree, func(i, j int) bool {
+		return tree[i].Depth > tree[j].Depth
+	})
+
+	ids := make([]ProcessIdentity, len(tree))
+	for i, p := range tree {
+		ids[i] = p.Identity
+	}
+	return ids
+}
+
+// ExpandKillTargets fills in Tree for the scope. A target whose owner is
+// already part of an earlier target's tree is merged into it, so no process
+// is signalled twice. csys itself and its ancestors are never included,
+// which matters for --group when csys runs from the same script as the
+// server.
+func ExpandKillTargets(targets []KillTarget, scope KillScope) ([]KillTarget, error) {
+	if scope == ScopeProcess {
+		return targets, nil
+	}
+
+	table, err := newProcessTable()
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]KillTarget, 0, len(targets))
+	claimed := make(map[int32]int)
+	// Each published port of a container has its own docker-proxy.
+	containers := make(map[string]int)
+	resolver := NewContainerResolver()
+
+	for _, t := range targets {
+		if t.PID == 0 || (scope == ScopeContainer && t.Container == nil) {
+			expanded = append(expanded, t)
+			continue
+		}
+		i, ok := claimed[t.PID]
+		if !ok && scope == ScopeContainer {
+			i, ok = containers[t.Container.ID]
+		}
+		if ok {
+			merged := append(expanded[i].Ports, t.Ports...)
+			sort.SliceStable(merged, func(a, b int) bool { return merged[a].Port < merged[b].Port })
+			expanded[i].Ports = merged
+			continue
+		}
+
+		// Descendants and group members are found from the owner's PID; if
+		// that no longer is the process that held the socket, they belong to
+		// a stranger. Left unexpanded, the owner's kill reports why.
+		if scope != ScopeContainer {
+			if err := t.Identity.verify(); err != nil {
+				expanded = append(expanded, t)
+				continue
+			}
+		}
+
+		var members []int32
+		switch scope {
+		case ScopeGroup:
+			members = table.group(t.PID)
+		case ScopeContainer:
+			members = table.container(t.Container.ID, resolver)
+			t.StopContainer = true
+			containers[t.Container.ID] = len(expanded)
+		default:
+			members = table.descendants(t.PID)
+		}
+
+		var pids []int32
+		for _, pid := range members {
+			if _, ok := claimed[pid]; ok || (table.protected[pid] && pid != t.PID) {
+				continue
+			}
+			pids = append(pids, pid)
+		}
+
+		t.Tree = table.tree(pids, t.PID, t.Identity)
+		for _, p := range t.Tree {
+			claimed[p.Identity.PID] = len(expanded)
+		}
+		expanded = append(expanded, t)
+	}
+
+	return expanded, nil
+}
+
+// TerminateTarget kills the target's processes in KillOrder and reports the
+// owner's outcome, with every other process under Related. When a container
+// is stopped through its docker-proxy, the container's init stands in for
+// the owner.
+func TerminateTarget(t KillTarget, opts KillOptions) KillResult {
+	ports := t.PortNumbers()
+	result := KillResult{
+		Port:        ports[0],
+		Ports:       ports,
+		PID:         t.PID,
+		ProcessName: t.ProcessName,
+	}
+	if t.PID == 0 {
+		result.Error = fmt.Sprintf("no process found for port %d", ports[0])
+		return result
+	}
+
+	primary := t.PID
+	if t.StopContainer {
+		result.Container = t.Container
+		if len(t.Tree) == 0 {
+			result.Error = fmt.Sprintf("container %s has no processes left to signal", t.Container.DisplayName())
+			return result
+		}
+		if !t.inTree(t.PID) {
+			primary = t.Tree[0].Identity.PID
+			result.PID = primary
+			result.ProcessName = t.Tree[0].Identity.Name
+		}
+	}
+
+	ids := t.KillOrder()
+	outcomes := TerminateProcesses(ids, opts)
+	primarySeen := false
+	for i, id := range ids {
+		o := outcomes[i]
+		if id.PID == primary {
+			primarySeen = true
+			if o.Err != nil {
+				result.Error = o.Err.Error()
+				continue
+			}
+			result.Killed = true
+			result.Signal = SignalName(o.Signal)
+			result.Escalated = o.Signal != opts.Signal
+			continue
+		}
+
+		related := ProcessKill{PID: id.PID, ProcessName: id.Name}
+		if o.Err != nil {
+			related.Error = o.Err.Error()
+		} else {
+			related.Signal = SignalName(o.Signal)
+		}
+		result.Related = append(result.Related, related)
+	}
+	// The owner exited or no longer verified when the tree was built; only
+	// the rest of it was signalled.
+	if !primarySeen {
+		result.Error = fmt.Sprintf("process %d exited or was replaced before it could be signalled", primary)
+	}
+	return result
+}
+
+func (t KillTarget) inTree(pid int32) bool {
+	for _, p := range t.Tree {
+		if p.Identity.PID == pid {
+			return true
+		}
+	}
+	return false
+}
+
+type processTable struct {
+	ppid      map[int32]int32
+	children  map[int32][]int32
+	pgid      map[int32]int
+	protected map[int32]bool
+}
+
+func newProcessTable() (*processTable, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	t := &processTable{
+		ppid:      make(map[int32]int32, len(procs)),
+		children:  make(map[int32][]int32),
+		pgid:      make(map[int32]int, len(procs)),
+		protected: make(map[int32]bool),
+	}
+	for _, p := range procs {
+		ppid, err := p.Ppid()
+		if err != nil {
+			continue
+		}
+		t.ppid[p.Pid] = ppid
+		t.children[ppid] = append(t.children[ppid], p.Pid)
+		if pgid, err := processGroupID(p.Pid); err == nil {
+			t.pgid[p.Pid] = pgid
+		}
+	}
+	for _, kids := range t.children {
+		sort.Slice(kids, func(i, j int) bool { return kids[i] < kids[j] })
+	}
+
+	// Never signal init, csys or anything csys runs under.
+	t.protected[1] = true
+	for pid := int32(os.Getpid()); pid > 0 && !t.protected[pid]; pid = t.ppid[pid] {
+		t.protected[pid] = true
+	}
+	return t, nil
+}
+
+func (t *processTable) descendants(pid int32) []int32 {
+	pids := []int32{pid}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, t.children[pids[i]]...)
+	}
+	return pids
+}
+
+func (t *processTable) group(pid int32) []int32 {
+	pgid, ok := t.pgid[pid]
+	if !ok {
+		return []int32{pid}
+	}
+	var pids []int32
+	for p, g := range t.pgid {
+		if g == pgid {
+			pids = append(pids, p)
+		}
+	}
+	return pids
+}
+
+// container returns the processes whose cgroup belongs to the container.
+func (t *processTable) container(id string, r *ContainerResolver) []int32 {
+	var pids []int32
+	for pid := range t.ppid {
+		if cid, _ := r.containerID(pid); cid == id {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// tree orders pids depth-first from their topmost members, with depths
+// relative to them. The owner keeps the identity captured at selection; if
+// that no longer verifies, neither it nor anything below it is included.
+func (t *processTable) tree(pids []int32, owner int32, ownerID ProcessIdentity) []TreeProcess {
+	in := make(map[int32]bool, len(pids))
+	for _, pid := range pids {
+		in[pid] = true
+	}
+
+	var roots []int32
+	for _, pid := range pids {
+		if !in[t.ppid[pid]] {
+			roots = append(roots, pid)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i] < roots[j] })
+
+	var tree []TreeProcess
+	var walk func(pid int32, depth int)
+	walk = func(pid int32, depth int) {
+		id := ownerID
+		if pid == owner {
+			if ownerID.verify() != nil {
+				return
+			}
+		} else {
+			var err error
+			if id, err = IdentifyProcess(pid); err != nil {
+				return // exited while the table was built
+			}
+		}
+		tree = append(tree, TreeProcess{Identity: id, PPID: t.ppid[pid], Depth: depth})
+		for _, child := range t.children[pid] {
+			if in[child] {
+				walk(child, depth+1)
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return tree
+}
@@ -0,0 +1,142 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdnet "net"
+	"strconv"
+	"time"
+)
+
+type PortWaitState string
+
+const (
+	PortListening PortWaitState = "listening"
+	PortFree      PortWaitState = "free"
+)
+
+// ParsePortWaitState accepts "listening" or "free".
+func ParsePortWaitState(s string) (PortWaitState, error) {
+	switch PortWaitState(s) {
+	case PortListening, PortFree:
+		return PortWaitState(s), nil
+	}
+	return "", fmt.Errorf("invalid state %q (must be listening or free)", s)
+}
+
+type PortWaitOptions struct {
+	Ports   []int
+	State   PortWaitState
+	Timeout time.Duration
+	// Interval is the delay between polls.
+	Interval time.Duration
+	// Host, if set, decides readiness with a TCP connect to Host:port instead
+	// of the local socket table, e.g. for a port published by a container or
+	// a service that is only ready once it accepts connections.
+	Host string
+	// Protocol is "tcp" or "udp"; only sockets of it count as listening.
+	// TCP when empty.
+	Protocol string
+}
+
+type PortWaitResult struct {
+	Port      int           `json:"port"`
+	State     PortWaitState `json:"state"`
+	Ready     bool          `json:"ready"`
+	ElapsedNS time.Duration `json:"elapsed_ns"`
+}
+
+type PortWaitReport struct {
+	Ready   bool             `json:"ready"`
+	Results []PortWaitResult `json:"results"`
+}
+
+const probeTimeout = 500 * time.Millisecond
+
+// WaitForPorts polls until every port reaches the wanted state or the
+// timeout passes. Ports that never got there are reported with Ready false.
+func WaitForPorts(ctx context.Context, opts PortWaitOptions) (*PortWaitReport, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 250 * time.Millisecond
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	results := make([]PortWaitResult, len(opts.Ports))
+	for i, port := range opts.Ports {
+		results[i] = PortWaitResult{Port: port, State: opts.State}
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		isListening, err := listeningProbe(opts.Host, opts.Protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		pending := false
+		for i := range results {
+			if results[i].Ready {
+				continue
+			}
+			if isListening(results[i].Port) == (opts.State == PortListening) {
+				results[i].Ready = true
+				results[i].ElapsedNS = time.Since(start)
+			} else {
+				pending = true
+			}
+		}
+
+		if !pending {
+			return &PortWaitReport{Ready: true, Results: results}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for i := range results {
+				if !results[i].Ready {
+					results[i].ElapsedNS = time.Since(start)
+				}
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &PortWaitReport{Ready: false, Results: results}, nil
+			}
+			return &PortWaitReport{Ready: false, Results: results}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// listeningProbe returns a check for one poll: a TCP connect when host is
+// set, otherwise a lookup in a single snapshot of the local socket table.
+func listeningProbe(host, protocol string) (func(port int) bool, error) {
+	if host != "" {
+		return func(port int) bool { return probePort(host, port) }, nil
+	}
+
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	listening, err := listeningPortSet(protocol)
+	if err != nil {
+		return nil, err
+	}
+	return func(port int) bool { return listening[port] }, nil
+}
+
+// probePort reports whether a TCP connection to host:port is accepted.
+func probePort(host string, port int) bool {
+	conn, err := stdnet.DialTimeout("tcp", stdnet.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
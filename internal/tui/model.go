@@ -0,0 +1,601 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+type Options struct {
+	Interval time.Duration
+	// CPUDetail starts the dashboard with per-core CPU bars shown.
+	CPUDetail bool
+	// Count stops the dashboard after this many samples; 0 runs until quit.
+	Count int
+	// GroupBy starts the process tab grouped by this key; the group view
+	// toggles with a and uses GroupByName when unset.
+	GroupBy system.ProcessGroupKey
+	// Collector produces the overview for every refresh; it keeps CPU
+	// counters between ticks so usage is measured over each interval.
+	Collector *system.Collector
+	// Recorder, if set, receives every sample for the end-of-session summary.
+	Recorder *system.SessionRecorder
+}
+
+type tab int
+
+const (
+	tabProcesses tab = iota
+	tabPorts
+)
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeFilter
+	modeSignal
+)
+
+// signalKeys maps the keys accepted by the signal prompt to the signal sent.
+var signalKeys = map[string]syscall.Signal{
+	"t": syscall.SIGTERM,
+	"k": syscall.SIGKILL,
+	"i": syscall.SIGINT,
+	"h": syscall.SIGHUP,
+}
+
+type tickMsg time.Time
+
+type dataMsg struct {
+	overview *system.Overview
+	procs    []system.ProcessInfo
+	ports    []system.PortInfo
+	err      error
+	at       time.Time
+}
+
+type signalMsg struct {
+	pid  int32
+	name string
+	sig  syscall.Signal
+	err  error
+}
+
+type model struct {
+	opts Options
+
+	width  int
+	height int
+
+	overview *system.Overview
+	procs    []system.ProcessInfo
+	ports    []system.PortInfo
+	err      error
+	updated  time.Time
+
+	samples int
+
+	tab       tab
+	cpuDetail bool
+	// tree shows processes as a parent/child hierarchy; collapsed holds the
+	// PIDs whose children are hidden.
+	tree      bool
+	collapsed map[int32]bool
+	// grouped shows one row per process group; group is the group opened
+	// with enter, whose processes are listed until esc.
+	grouped       bool
+	groupBy       system.ProcessGroupKey
+	groups        []system.ProcessGroup
+	group         string
+	selectedGroup string
+	sortBy        system.ProcessSortKey
+	mode          mode
+	filter        string
+	status        string
+	cursor        [2]int
+	offset        [2]int
+	selected      [2]int32
+	// signalTarget is the process the signal prompt was opened for.
+	signalTarget system.ProcessIdentity
+}
+
+func Run(opts Options) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.Collector == nil {
+		opts.Collector = system.NewCollector(5, system.DefaultSampleWindow)
+	}
+
+	m := model{
+		opts:      opts,
+		cpuDetail: opts.CPUDetail,
+		sortBy:    system.SortByRSS,
+		collapsed: make(map[int32]bool),
+		grouped:   opts.GroupBy != system.GroupByNone,
+		groupBy:   opts.GroupBy,
+	}
+	if m.groupBy == system.GroupByNone {
+		m.groupBy = system.GroupByName
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err := p.Run()
+	if err == tea.ErrInterrupted {
+		return nil
+	}
+	return err
+}
+
+func (m model) Init() tea.Cmd {
+	return m.fetch
+}
+
+func (m model) fetch() tea.Msg {
+	msg := dataMsg{}
+
+	msg.overview, msg.err = m.opts.Collector.Collect()
+	msg.at = time.Now()
+	if msg.err != nil {
+		return msg
+	}
+
+	msg.procs = m.opts.Collector.Processes()
+	system.AttachContainers(msg.procs)
+
+	msg.ports, msg.err = system.GetListeningPorts()
+	return msg
+}
+
+func tick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+func sendSignal(id system.ProcessIdentity, sig syscall.Signal) tea.Cmd {
+	return func() tea.Msg {
+		return signalMsg{pid: id.PID, name: id.Name, sig: sig, err: system.SignalIdentified(id, sig)}
+	}
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampCursor()
+		return m, nil
+
+	case tickMsg:
+		return m, m.fetch
+
+	case dataMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.overview = msg.overview
+			m.procs = msg.procs
+			m.ports = msg.ports
+			m.updated = msg.at
+			m.regroup()
+			m.restoreSelection()
+
+			m.samples++
+			if m.opts.Recorder != nil {
+				m.opts.Recorder.Record(msg.overview, msg.at)
+			}
+			if m.opts.Count > 0 && m.samples >= m.opts.Count {
+				return m, tea.Quit
+			}
+		}
+		return m, tick(m.opts.Interval)
+
+	case signalMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("✗ %v", msg.err)
+		} else {
+			m.status = fmt.Sprintf("✓ Sent %s to %s [PID: %d]", system.SignalName(msg.sig), msg.name, msg.pid)
+		}
+		// The next tick picks up the change; fetching now would race the
+		// Collector with the pending tick.
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeFilter:
+			return m.updateFilter(msg)
+		case modeSignal:
+			return m.updateSignal(msg)
+		}
+		return m.updateNormal(msg)
+	}
+
+	return m, nil
+}
+
+func (m model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "tab", "shift+tab":
+		m.setTab((m.tab + 1) % 2)
+	case "1":
+		m.setTab(tabProcesses)
+	case "2":
+		m.setTab(tabPorts)
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.listHeight())
+	case "pgdown":
+		m.moveCursor(m.listHeight())
+	case "home", "g":
+		m.moveCursor(-m.rowCount())
+	case "end", "G":
+		m.moveCursor(m.rowCount())
+	case "d":
+		m.cpuDetail = !m.cpuDetail
+		m.clampCursor()
+	case "m":
+		m.setSort(system.SortByRSS)
+	case "c":
+		m.setSort(system.SortByCPU)
+	case "p":
+		m.setSort(system.SortByPID)
+	case "t":
+		if m.tab == tabProcesses {
+			m.tree = !m.tree
+			m.grouped = false
+			m.group = ""
+			m.restoreSelection()
+		}
+	case "a":
+		if m.tab == tabProcesses {
+			m.grouped = !m.grouped
+			m.tree = false
+			m.group = ""
+			m.regroup()
+			m.restoreSelection()
+		}
+	case "enter", " ":
+		if m.showingGroups() {
+			m.openGroup()
+		} else {
+			m.setCollapsed(!m.collapsed[m.selected[tabProcesses]])
+		}
+	case "left", "h":
+		m.setCollapsed(true)
+	case "right", "l":
+		m.setCollapsed(false)
+	case "/":
+		m.mode = modeFilter
+		m.status = ""
+	case "esc":
+		// Leaving an opened group comes first; the next esc clears the filter.
+		if m.grouped && m.group != "" && m.tab == tabProcesses {
+			m.group = ""
+		} else {
+			m.filter = ""
+		}
+		m.status = ""
+		m.restoreSelection()
+	case "x":
+		id, err := m.identifyTarget()
+		if err != nil {
+			m.status = fmt.Sprintf("✗ %v", err)
+			break
+		}
+		m.signalTarget = id
+		m.mode = modeSignal
+		m.status = ""
+	}
+	return m, nil
+}
+
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.mode = modeNormal
+	case tea.KeyEsc:
+		m.mode = modeNormal
+		m.filter = ""
+	case tea.KeyBackspace:
+		if len(m.filter) > 0 {
+			r := []rune(m.filter)
+			m.filter = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	}
+	m.restoreSelection()
+	return m, nil
+}
+
+func (m model) updateSignal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m, tea.Quit
+	}
+
+	m.mode = modeNormal
+	sig, ok := signalKeys[msg.String()]
+	if !ok {
+		return m, nil
+	}
+
+	return m, sendSignal(m.signalTarget, sig)
+}
+
+// identifyTarget pins the selected process when the signal prompt opens. The
+// row may be seconds old, so a PID that now runs something else is refused,
+// and the signal later goes only to the process identified here.
+func (m model) identifyTarget() (system.ProcessIdentity, error) {
+	pid, name := m.selectedTarget()
+	if pid == 0 {
+		return system.ProcessIdentity{}, fmt.Errorf("no process selected")
+	}
+	id, err := system.IdentifyProcess(pid)
+	if err != nil {
+		return system.ProcessIdentity{}, fmt.Errorf("process %d has exited", pid)
+	}
+	if id.Name != name {
+		return system.ProcessIdentity{}, fmt.Errorf("PID %d now belongs to a different process (%s)", pid, id.Name)
+	}
+	return id, nil
+}
+
+func (m *model) setTab(t tab) {
+	m.tab = t
+	m.restoreSelection()
+}
+
+func (m *model) setSort(key system.ProcessSortKey) {
+	m.sortBy = key
+	m.restoreSelection()
+}
+
+// visibleProcs returns the filtered process list in the current sort order,
+// or in tree order when the tree view is on.
+func (m model) visibleProcs() []system.ProcessInfo {
+	if m.tree {
+		rows := m.treeRows()
+		procs := make([]system.ProcessInfo, len(rows))
+		for i, row := range rows {
+			procs[i] = row.Node.ProcessInfo
+		}
+		return procs
+	}
+
+	var members map[int32]bool
+	if m.grouped && m.group != "" {
+		members = make(map[int32]bool)
+		for _, g := range m.groups {
+			if g.Key == m.group {
+				for _, pid := range g.PIDs {
+					members[pid] = true
+				}
+			}
+		}
+	}
+
+	procs := make([]system.ProcessInfo, 0, len(m.procs))
+	for _, p := range m.procs {
+		if members != nil && !members[p.PID] {
+			continue
+		}
+		if m.matchProcess(p) {
+			procs = append(procs, p)
+		}
+	}
+
+	system.SortProcesses(procs, m.sortBy)
+	return procs
+}
+
+// treeRows keeps the ancestors of filter matches so they stay in context.
+func (m model) treeRows() []display.ProcessTreeRow {
+	var match func(system.ProcessInfo) bool
+	if m.filter != "" {
+		match = m.matchProcess
+	}
+	roots := system.BuildProcessTree(m.procs, m.sortBy)
+	return display.FlattenProcessTree(roots, m.collapsed, match)
+}
+
+// showingGroups reports whether the process tab lists groups rather than
+// processes.
+func (m model) showingGroups() bool {
+	return m.tab == tabProcesses && m.grouped && m.group == ""
+}
+
+// regroup aggregates the latest sample; it runs once per refresh rather
+// than per render because grouping by exe, user or cgroup reads /proc.
+func (m *model) regroup() {
+	if !m.grouped {
+		m.groups = nil
+		return
+	}
+	m.groups = system.GroupProcesses(m.procs, m.groupBy)
+}
+
+// visibleGroups returns the groups whose key matches the filter, in the
+// current sort order (CPU for c, memory otherwise).
+func (m model) visibleGroups() []system.ProcessGroup {
+	filter := strings.ToLower(m.filter)
+	groups := make([]system.ProcessGroup, 0, len(m.groups))
+	for _, g := range m.groups {
+		if filter == "" || strings.Contains(strings.ToLower(g.Key), filter) {
+			groups = append(groups, g)
+		}
+	}
+	system.SortProcessGroups(groups, m.sortBy)
+	return groups
+}
+
+// openGroup lists the processes of the selected group. The filter was
+// matching group keys, so it is cleared.
+func (m *model) openGroup() {
+	groups := m.visibleGroups()
+	i := m.cursor[tabProcesses]
+	if i >= len(groups) {
+		return
+	}
+	m.group = groups[i].Key
+	m.selectedGroup = m.group
+	m.filter = ""
+	m.cursor[tabProcesses] = 0
+	m.offset[tabProcesses] = 0
+	m.selected[tabProcesses] = 0
+	m.restoreSelection()
+}
+
+func (m model) matchProcess(p system.ProcessInfo) bool {
+	filter := strings.ToLower(m.filter)
+	return filter == "" ||
+		strings.Contains(strings.ToLower(p.Name), filter) ||
+		strings.Contains(strconv.Itoa(int(p.PID)), filter)
+}
+
+// setCollapsed hides or shows the children of the selected process in the
+// tree view.
+func (m *model) setCollapsed(collapsed bool) {
+	if !m.tree || m.tab != tabProcesses {
+		return
+	}
+	pid := m.selected[tabProcesses]
+	if collapsed {
+		m.collapsed[pid] = true
+	} else {
+		delete(m.collapsed, pid)
+	}
+	m.restoreSelection()
+}
+
+func (m model) visiblePorts() []system.PortInfo {
+	filter := strings.ToLower(m.filter)
+	ports := make([]system.PortInfo, 0, len(m.ports))
+	for _, p := range m.ports {
+		if filter != "" &&
+			!strings.Contains(strings.ToLower(p.ProcessName), filter) &&
+			!strings.Contains(strconv.Itoa(p.Port), filter) {
+			continue
+		}
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+func (m model) rowCount() int {
+	if m.tab == tabPorts {
+		return len(m.visiblePorts())
+	}
+	if m.showingGroups() {
+		return len(m.visibleGroups())
+	}
+	return len(m.visibleProcs())
+}
+
+// selectedTarget returns the process to signal; group rows have none.
+func (m model) selectedTarget() (int32, string) {
+	i := m.cursor[m.tab]
+	if m.showingGroups() {
+		return 0, ""
+	}
+	if m.tab == tabPorts {
+		ports := m.visiblePorts()
+		if i < len(ports) {
+			return ports[i].PID, ports[i].ProcessName
+		}
+		return 0, ""
+	}
+
+	procs := m.visibleProcs()
+	if i < len(procs) {
+		return procs[i].PID, procs[i].Name
+	}
+	return 0, ""
+}
+
+func (m *model) moveCursor(delta int) {
+	m.cursor[m.tab] += delta
+	m.clampCursor()
+	m.selected[m.tab], _ = m.selectedTarget()
+	m.selectGroup()
+}
+
+// selectGroup remembers the group under the cursor by key, as PIDs do not
+// identify a group.
+func (m *model) selectGroup() {
+	if !m.showingGroups() {
+		return
+	}
+	if groups := m.visibleGroups(); m.cursor[m.tab] < len(groups) {
+		m.selectedGroup = groups[m.cursor[m.tab]].Key
+	}
+}
+
+// restoreSelection keeps the cursor on the same PID (or group) after the
+// list is refreshed, re-sorted or filtered.
+func (m *model) restoreSelection() {
+	want := m.selected[m.tab]
+	if m.showingGroups() {
+		for i, g := range m.visibleGroups() {
+			if g.Key == m.selectedGroup {
+				m.cursor[m.tab] = i
+				break
+			}
+		}
+	} else if want != 0 {
+		if m.tab == tabPorts {
+			for i, p := range m.visiblePorts() {
+				if p.PID == want {
+					m.cursor[m.tab] = i
+					break
+				}
+			}
+		} else {
+			for i, p := range m.visibleProcs() {
+				if p.PID == want {
+					m.cursor[m.tab] = i
+					break
+				}
+			}
+		}
+	}
+	m.clampCursor()
+	m.selected[m.tab], _ = m.selectedTarget()
+	m.selectGroup()
+}
+
+func (m *model) clampCursor() {
+	rows := m.rowCount()
+	c := m.cursor[m.tab]
+	if c >= rows {
+		c = rows - 1
+	}
+	if c < 0 {
+		c = 0
+	}
+	m.cursor[m.tab] = c
+
+	height := m.listHeight()
+	if c < m.offset[m.tab] {
+		m.offset[m.tab] = c
+	}
+	if c >= m.offset[m.tab]+height {
+		m.offset[m.tab] = c - height + 1
+	}
+	if m.offset[m.tab] < 0 {
+		m.offset[m.tab] = 0
+	}
+}
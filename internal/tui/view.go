@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/iyushkarki/csys/internal/display"
+	"github.com/iyushkarki/csys/internal/system"
+)
+
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#7D56F4"))
+
+	activeTabStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#7D56F4")).
+			Padding(0, 1)
+
+	inactiveTabStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#626262")).
+				Padding(0, 1)
+
+	columnStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#626262"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#3C3C3C"))
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#626262"))
+
+	promptStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500")).
+			Bold(true)
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF0000")).
+			Bold(true)
+)
+
+func (m model) viewMetrics() string {
+	opts := display.OverviewOptions{CPUDetail: m.cpuDetail}
+	if m.opts.Recorder != nil {
+		opts.RxHistory, opts.TxHistory = m.opts.Recorder.NetHistory()
+	}
+	return display.FormatMetrics(m.overview, opts)
+}
+
+// listHeight is what is left for list rows after the tabs, metrics, column
+// header, footer and the blank lines between them.
+func (m model) listHeight() int {
+	chrome := 5
+	if m.overview != nil {
+		chrome += lipgloss.Height(m.viewMetrics())
+	}
+
+	h := m.height - chrome
+	if h < 1 {
+		return 1
+	}
+	return h
+}
+
+func (m model) View() string {
+	if m.overview == nil {
+		if m.err != nil {
+			return errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+		}
+		return "Collecting system metrics...\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(m.viewTabs() + "\n\n")
+	b.WriteString(m.viewMetrics() + "\n\n")
+
+	if m.tab == tabPorts {
+		b.WriteString(m.viewPorts())
+	} else {
+		b.WriteString(m.viewProcesses())
+	}
+
+	b.WriteString(m.viewFooter())
+	return b.String()
+}
+
+func (m model) viewTabs() string {
+	names := []string{"1 Processes", "2 Ports"}
+	var tabs []string
+	for i, name := range names {
+		if tab(i) == m.tab {
+			tabs = append(tabs, activeTabStyle.Render(name))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(name))
+		}
+	}
+
+	header := titleStyle.Render("csys live") + "  " + strings.Join(tabs, " ")
+	header += helpStyle.Render(fmt.Sprintf("  Last updated: %s", m.updated.Format("15:04:05")))
+	if m.opts.Count > 0 {
+		header += helpStyle.Render(fmt.Sprintf("  Sample %d/%d", m.samples, m.opts.Count))
+	}
+	return header
+}
+
+func (m model) viewProcesses() string {
+	if m.tree {
+		return m.viewProcessTree()
+	}
+	if m.showingGroups() {
+		return m.viewProcessGroups()
+	}
+
+	procs := m.visibleProcs()
+	header := fmt.Sprintf("%7s  %-32s  %10s  %6s", "PID", "NAME", "MEMORY", "CPU%")
+	containers := false
+	for _, p := range procs {
+		containers = containers || p.Container != nil
+	}
+	if containers {
+		header += "  CONTAINER"
+	}
+
+	var rows []string
+	for _, p := range procs {
+		row := fmt.Sprintf("%7d  %-32s  %10s  %6.1f",
+			p.PID,
+			truncate(p.Name, 32),
+			humanize.IBytes(p.Memory),
+			p.CPUPercent,
+		)
+		if containers {
+			row += "  " + containerColumn(p.Container)
+		}
+		rows = append(rows, row)
+	}
+
+	title := fmt.Sprintf("sort: %s", m.sortBy)
+	if m.grouped {
+		title = fmt.Sprintf("%s: %s, sort: %s", m.groupBy, m.group, m.sortBy)
+	}
+	return m.viewList(header, title, rows)
+}
+
+func (m model) viewProcessGroups() string {
+	groups := m.visibleGroups()
+	header := fmt.Sprintf("%-40s  %5s  %10s  %7s", strings.ToUpper(string(m.groupBy)), "PROCS", "Σ MEMORY", "Σ CPU%")
+
+	rows := make([]string, len(groups))
+	for i, g := range groups {
+		rows[i] = fmt.Sprintf("%s  %5d  %10s  %7.1f",
+			display.PadRunes(display.TruncateLeft(g.Key, 40), 40),
+			g.Processes,
+			humanize.IBytes(g.Memory),
+			g.CPUPercent,
+		)
+	}
+
+	title := fmt.Sprintf("%d groups, sort: %s", len(groups), m.sortBy)
+	return m.viewList(header, title, rows)
+}
+
+// viewProcessTree shows subtree totals; ▸ marks a collapsed process.
+func (m model) viewProcessTree() string {
+	rows := m.treeRows()
+	header := fmt.Sprintf("%7s  %-40s  %10s  %7s  %5s", "PID", "NAME", "Σ MEMORY", "Σ CPU%", "PROCS")
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		n := row.Node
+		marker := ""
+		if row.Collapsed {
+			marker = "▸"
+		}
+		lines[i] = fmt.Sprintf("%7d  %s  %10s  %7.1f  %5d",
+			n.PID,
+			display.PadRunes(row.Prefix+marker+n.Name, 40),
+			humanize.IBytes(n.SubtreeMemory),
+			n.SubtreeCPUPercent,
+			n.Descendants+1,
+		)
+	}
+
+	title := fmt.Sprintf("tree, sort: %s", m.sortBy)
+	return m.viewList(header, title, lines)
+}
+
+func (m model) viewPorts() string {
+	ports := m.visiblePorts()
+	header := fmt.Sprintf("%-5s  %5s  %-22s  %-32s  %7s  %10s", "PROTO", "PORT", "ADDRESS", "PROCESS", "PID", "MEMORY")
+	containers := false
+	for _, p := range ports {
+		containers = containers || p.Container != nil
+	}
+	if containers {
+		header += "  CONTAINER"
+	}
+
+	var rows []string
+	for _, p := range ports {
+		row := fmt.Sprintf("%-5s  %5d  %-22s  %-32s  %7d  %10s",
+			strings.ToUpper(p.Protocol),
+			p.Port,
+			truncate(p.Address, 22),
+			truncate(p.ProcessName, 32),
+			p.PID,
+			humanize.IBytes(p.Memory),
+		)
+		if containers {
+			row += "  " + containerColumn(p.Container)
+		}
+		rows = append(rows, row)
+	}
+
+	return m.viewList(header, fmt.Sprintf("%d listening", len(ports)), rows)
+}
+
+func (m model) viewList(header, title string, rows []string) string {
+	var b strings.Builder
+	b.WriteString(columnStyle.Render(header) + "  " + helpStyle.Render(title) + "\n")
+
+	height := m.listHeight()
+	offset := m.offset[m.tab]
+	cursor := m.cursor[m.tab]
+
+	for i := offset; i < len(rows) && i < offset+height; i++ {
+		if i == cursor {
+			b.WriteString(selectedStyle.Render(rows[i]) + "\n")
+		} else {
+			b.WriteString(rows[i] + "\n")
+		}
+	}
+
+	// Pad so the footer stays on the last line.
+	for i := len(rows) - offset; i < height; i++ {
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (m model) viewFooter() string {
+	switch m.mode {
+	case modeFilter:
+		return promptStyle.Render("Filter: ") + m.filter + "█"
+	case modeSignal:
+		return promptStyle.Render(fmt.Sprintf(
+			"Signal %s [PID: %d]: [t]erm [k]ill [i]nt [h]up, any other key cancels", m.signalTarget.Name, m.signalTarget.PID))
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
+	if m.status != "" {
+		return m.status
+	}
+
+	help := "q quit • tab switch • ↑/↓ move • / filter • x signal • d cpu detail"
+	if m.tab == tabProcesses {
+		help += " • m/c/p sort • t tree • a group"
+		switch {
+		case m.tree:
+			help += " • enter/←/→ collapse"
+		case m.showingGroups():
+			help += " • enter open group"
+		case m.grouped:
+			help += " • esc back to groups"
+		}
+	}
+	if m.filter != "" {
+		help += fmt.Sprintf(" • filter: %q (esc clears)", m.filter)
+	}
+	return helpStyle.Render(help)
+}
+
+// containerColumn names the container, with an arrow for a docker-proxy
+// publishing its port.
+func containerColumn(c *system.ContainerInfo) string {
+	switch {
+	case c == nil:
+		return "-"
+	case c.Proxy:
+		return "→ " + truncate(c.DisplayName(), 20)
+	}
+	return truncate(c.DisplayName(), 22)
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) > maxLen {
+		return s[:maxLen-3] + "..."
+	}
+	return s
+}